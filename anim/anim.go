@@ -0,0 +1,320 @@
+// Package anim tweens values over time for animating canvas drawings.
+//
+// Animations advance by explicit time steps, either from an ebiten Update
+// (using Tick(ebiten.TPS())) or from a test clock, so they are deterministic.
+package anim
+
+import (
+	"image/color"
+	"math"
+	"time"
+)
+
+// Forever repeats an animation without end
+const Forever = -1
+
+// Animator is anything that advances with time
+type Animator interface {
+	// Update advances the animation by dt, returning true when finished
+	Update(dt time.Duration) bool
+	// Done reports whether the animation is finished
+	Done() bool
+	// Reset returns the animation to its beginning
+	Reset()
+	// Leftover returns the time given to Update since the animation finished (0 if it has not),
+	// so that an animation that follows can start on time
+	Leftover() time.Duration
+}
+
+// Tick returns the duration of one update at the specified ticks per second
+func Tick(tps int) time.Duration {
+	if tps <= 0 {
+		return 0
+	}
+	return time.Second / time.Duration(tps)
+}
+
+// Tween changes a value from From to To over Duration, shaped by Ease.
+// If Target is set, it is updated with the current value.
+type Tween struct {
+	From, To float64
+	Duration time.Duration
+	Delay    time.Duration
+	Ease     Easing
+	Repeat   int  // number of extra plays, or Forever
+	Yoyo     bool // alternate direction on each play
+	Target   *float32
+	elapsed  time.Duration
+	plays    int
+}
+
+// NewTween makes a tween from a to b over d using the easing function
+func NewTween(a, b float64, d time.Duration, ease Easing) *Tween {
+	return &Tween{From: a, To: b, Duration: d, Ease: ease}
+}
+
+// Bind sets the value to be updated by the tween
+func (t *Tween) Bind(p *float32) *Tween {
+	t.Target = p
+	t.set()
+	return t
+}
+
+// Loop sets the number of extra plays (or Forever), and whether to reverse direction
+func (t *Tween) Loop(n int, yoyo bool) *Tween {
+	t.Repeat = n
+	t.Yoyo = yoyo
+	return t
+}
+
+// Progress returns the fraction (0-1) of the current play that has elapsed
+func (t *Tween) Progress() float64 {
+	run := t.elapsed - t.Delay
+	switch {
+	case run <= 0:
+		return 0
+	case t.Duration <= 0 || run >= t.Duration:
+		return 1
+	}
+	return float64(run) / float64(t.Duration)
+}
+
+// Value returns the current value
+func (t *Tween) Value() float64 {
+	p := t.Progress()
+	if t.Yoyo && t.plays%2 == 1 {
+		p = 1 - p
+	}
+	ease := t.Ease
+	if ease == nil {
+		ease = Linear
+	}
+	return Lerp(t.From, t.To, ease(p))
+}
+
+// Update advances the tween by dt, returning true when finished
+func (t *Tween) Update(dt time.Duration) bool {
+	t.elapsed += dt
+	for t.Progress() >= 1 && !t.Done() && t.Duration > 0 {
+		if t.Repeat != Forever && t.plays >= t.Repeat {
+			break
+		}
+		t.elapsed -= t.Duration
+		t.plays++
+	}
+	t.set()
+	return t.Done()
+}
+
+// Done reports whether the tween has finished all plays
+func (t *Tween) Done() bool {
+	if t.Repeat == Forever {
+		return false
+	}
+	return t.plays >= t.Repeat && t.Progress() >= 1
+}
+
+// Leftover returns the time since the tween finished
+func (t *Tween) Leftover() time.Duration {
+	if !t.Done() {
+		return 0
+	}
+	return t.elapsed - t.Delay - max(t.Duration, 0)
+}
+
+// Reset returns the tween to its beginning
+func (t *Tween) Reset() {
+	t.elapsed = 0
+	t.plays = 0
+	t.set()
+}
+
+// set updates the target, if any
+func (t *Tween) set() {
+	if t.Target != nil {
+		*t.Target = float32(t.Value())
+	}
+}
+
+// ColorTween changes a color by interpolating its components
+type ColorTween struct {
+	From, To color.NRGBA
+	Target   *color.NRGBA
+	*Tween
+}
+
+// NewColorTween makes a color tween from a to b over d using the easing function
+func NewColorTween(a, b color.NRGBA, d time.Duration, ease Easing) *ColorTween {
+	return &ColorTween{From: a, To: b, Tween: NewTween(0, 1, d, ease)}
+}
+
+// Bind sets the color to be updated by the tween
+func (c *ColorTween) Bind(p *color.NRGBA) *ColorTween {
+	c.Target = p
+	*p = c.Color()
+	return c
+}
+
+// Color returns the current color
+func (c *ColorTween) Color() color.NRGBA {
+	return LerpColor(c.From, c.To, c.Value())
+}
+
+// Update advances the tween by dt, returning true when finished
+func (c *ColorTween) Update(dt time.Duration) bool {
+	done := c.Tween.Update(dt)
+	if c.Target != nil {
+		*c.Target = c.Color()
+	}
+	return done
+}
+
+// Reset returns the tween to its beginning
+func (c *ColorTween) Reset() {
+	c.Tween.Reset()
+	if c.Target != nil {
+		*c.Target = c.Color()
+	}
+}
+
+// Sequence plays animations one after another
+type Sequence struct {
+	Items  []Animator
+	Repeat int
+	index  int
+	plays  int
+	left   time.Duration // time since the sequence finished
+}
+
+// Seq makes a sequence of animations
+func Seq(a ...Animator) *Sequence {
+	return &Sequence{Items: a}
+}
+
+// Update advances the current animation by dt, returning true when all are finished.
+// Time left over when an animation finishes goes to the next, so the sequence does not drift.
+func (s *Sequence) Update(dt time.Duration) bool {
+	if s.Done() {
+		s.left += dt
+		return true
+	}
+	start := dt
+	for {
+		a := s.Items[s.index]
+		if !a.Update(dt) {
+			return false
+		}
+		dt = a.Leftover()
+		s.index++
+		if s.index < len(s.Items) {
+			continue
+		}
+		if s.Repeat != Forever && s.plays >= s.Repeat {
+			s.left = dt
+			return true
+		}
+		s.plays++
+		s.index = 0
+		for _, a := range s.Items {
+			a.Reset()
+		}
+		// stop if a play took no time, since it would repeat without end
+		if dt <= 0 || dt >= start {
+			return false
+		}
+		start = dt
+	}
+}
+
+// Done reports whether all animations in the sequence are finished
+func (s *Sequence) Done() bool {
+	return s.index >= len(s.Items)
+}
+
+// Leftover returns the time since the sequence finished
+func (s *Sequence) Leftover() time.Duration {
+	if !s.Done() {
+		return 0
+	}
+	return s.left
+}
+
+// Reset returns the sequence to its beginning
+func (s *Sequence) Reset() {
+	s.index, s.plays, s.left = 0, 0, 0
+	for _, a := range s.Items {
+		a.Reset()
+	}
+}
+
+// Group plays animations at the same time
+type Group []Animator
+
+// Update advances every animation by dt, returning true when all are finished
+func (g Group) Update(dt time.Duration) bool {
+	done := true
+	for _, a := range g {
+		if !a.Update(dt) {
+			done = false
+		}
+	}
+	return done
+}
+
+// Done reports whether all animations in the group are finished
+func (g Group) Done() bool {
+	for _, a := range g {
+		if !a.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Leftover returns the time since the last animation in the group finished
+func (g Group) Leftover() time.Duration {
+	if !g.Done() {
+		return 0
+	}
+	var left time.Duration
+	for i, a := range g {
+		if l := a.Leftover(); i == 0 || l < left {
+			left = l
+		}
+	}
+	return left
+}
+
+// Reset returns every animation in the group to its beginning
+func (g Group) Reset() {
+	for _, a := range g {
+		a.Reset()
+	}
+}
+
+// Lerp returns the value t (0-1) of the way between a and b
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// LerpColor returns the color t (0-1) of the way between a and b
+func LerpColor(a, b color.NRGBA, t float64) color.NRGBA {
+	return color.NRGBA{
+		R: lerp8(a.R, b.R, t),
+		G: lerp8(a.G, b.G, t),
+		B: lerp8(a.B, b.B, t),
+		A: lerp8(a.A, b.A, t),
+	}
+}
+
+// lerp8 interpolates color components, limiting the result to 0-255
+func lerp8(a, b uint8, t float64) uint8 {
+	v := math.Round(Lerp(float64(a), float64(b), t))
+	switch {
+	case v < 0:
+		return 0
+	case v > 255:
+		return 255
+	}
+	return uint8(v)
+}
@@ -0,0 +1,142 @@
+package anim
+
+import "math"
+
+// Easing maps the fraction of elapsed time (0-1) to the fraction of change (usually 0-1)
+type Easing func(t float64) float64
+
+// Linear is constant speed
+func Linear(t float64) float64 { return t }
+
+// InQuad accelerates from zero
+func InQuad(t float64) float64 { return t * t }
+
+// OutQuad decelerates to zero
+func OutQuad(t float64) float64 { return t * (2 - t) }
+
+// InOutQuad accelerates, then decelerates
+func InOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// InCubic accelerates from zero
+func InCubic(t float64) float64 { return t * t * t }
+
+// OutCubic decelerates to zero
+func OutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// InOutCubic accelerates, then decelerates
+func InOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return 0.5*t*t*t + 1
+}
+
+// InSine accelerates along a sine curve
+func InSine(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) }
+
+// OutSine decelerates along a sine curve
+func OutSine(t float64) float64 { return math.Sin(t * math.Pi / 2) }
+
+// InOutSine accelerates, then decelerates along a sine curve
+func InOutSine(t float64) float64 { return -(math.Cos(math.Pi*t) - 1) / 2 }
+
+// InExpo accelerates exponentially
+func InExpo(t float64) float64 {
+	if t == 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// OutExpo decelerates exponentially
+func OutExpo(t float64) float64 {
+	if t == 1 {
+		return 1
+	}
+	return 1 - math.Pow(2, -10*t)
+}
+
+// InOutExpo accelerates, then decelerates exponentially
+func InOutExpo(t float64) float64 {
+	switch {
+	case t == 0 || t == 1:
+		return t
+	case t < 0.5:
+		return math.Pow(2, 20*t-10) / 2
+	default:
+		return (2 - math.Pow(2, -20*t+10)) / 2
+	}
+}
+
+// InBack pulls back before moving forward
+func InBack(t float64) float64 {
+	const c1 = 1.70158
+	return (c1+1)*t*t*t - c1*t*t
+}
+
+// OutBack overshoots the end, then settles
+func OutBack(t float64) float64 {
+	const c1 = 1.70158
+	t--
+	return 1 + (c1+1)*t*t*t + c1*t*t
+}
+
+// OutElastic overshoots and oscillates around the end
+func OutElastic(t float64) float64 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*(2*math.Pi/3)) + 1
+}
+
+// OutBounce bounces against the end
+func OutBounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// InBounce bounces against the start
+func InBounce(t float64) float64 { return 1 - OutBounce(1-t) }
+
+// Easings maps names to easing functions
+var Easings = map[string]Easing{
+	"linear":     Linear,
+	"inquad":     InQuad,
+	"outquad":    OutQuad,
+	"inoutquad":  InOutQuad,
+	"incubic":    InCubic,
+	"outcubic":   OutCubic,
+	"inoutcubic": InOutCubic,
+	"insine":     InSine,
+	"outsine":    OutSine,
+	"inoutsine":  InOutSine,
+	"inexpo":     InExpo,
+	"outexpo":    OutExpo,
+	"inoutexpo":  InOutExpo,
+	"inback":     InBack,
+	"outback":    OutBack,
+	"outelastic": OutElastic,
+	"inbounce":   InBounce,
+	"outbounce":  OutBounce,
+}
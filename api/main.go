@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -74,7 +75,6 @@ func work(screen *ebiten.Image) {
 
 	// Title
 	canvas.Text(fx, top, textsize*1.5, "Ebiten Canvas API", txcolor)
-	yp := top - halfvs
 
 	// API labels
 	funcnames := []string{
@@ -101,15 +101,23 @@ func work(screen *ebiten.Image) {
 		"Stroked or filled Polygon using points in (x,y)",
 		"Image anchored at the top left corner or center at (x,y)",
 	}
-	for i, ly := 0, yp; i < len(funcnames); i++ {
+	// one row per function, below the title: the label on the left, the example at objx
+	n := float32(len(funcnames))
+	rows := layout.Rect{X: 0, Y: top - vspace*n, W: 100, H: vspace * n}.Rows(0, layout.Even(len(funcnames))...)
+	row := func(i int) float32 {
+		_, y := rows[i].Center()
+		return y
+	}
+	for i := range funcnames {
+		ly := row(i)
 		canvas.Text(fx, ly, textsize, funcnames[i], txcolor)
 		canvas.Text(fx, ly-(textsize*1.2), textsize*0.75, funcdesc[i], descolor)
-		ly -= vspace
 	}
 
 	// Text
 	message := "hello"
 	wmessage := "This is text wrapped at a specified width"
+	yp := row(0)
 	labelx := objx - halfhs
 	canvas.CText(labelx, yp, textsize, message, txcolor)
 	canvas.Circle(labelx, yp, dotsize, red)
@@ -121,32 +129,32 @@ func work(screen *ebiten.Image) {
 	canvas.Circle(labelx, yp, dotsize, red)
 	canvas.Circle(objx, yp+halfvs, dotsize, red)
 	canvas.RText(objx, yp+halfvs, 45, textsize, message, txcolor)
-	yp -= vspace
+	yp = row(1)
 	canvas.TextWrap(objx-hspace, yp+(halfvs/3), 20, textsize*0.8, wmessage, txcolor)
 
 	// Circle
-	yp -= vspace
+	yp = row(2)
 	canvas.Circle(objx, yp, halfhs, red)
 	canvas.Circle(objx, yp, dotsize, white)
 
 	// Rect
-	yp -= vspace
+	yp = row(3)
 	canvas.Rect(objx, yp, 20, halfvs, green)
 	canvas.Circle(objx, yp, dotsize, red)
 
 	// Square
-	yp -= vspace
+	yp = row(4)
 	canvas.Square(objx, yp, halfhs, green)
 	canvas.Circle(objx, yp, dotsize, red)
 
 	// Arc
-	yp -= vspace
+	yp = row(5)
 	canvas.Arc(objx, yp, halfhs, 0, 180, yellow)
 	canvas.StrokedArc(objx, yp, halfhs, 0, 180, linewidth, red)
 	canvas.Circle(objx, yp, dotsize, red)
 
 	// Curve
-	yp -= vspace
+	yp = row(6)
 	curvex := []float32{objx - halfhs, objx + halfhs, objx + halfhs}
 	curvey := []float32{yp, yp + halfvs, yp}
 	canvas.Curve(curvex[0], curvey[0], curvex[1], curvey[1], curvex[2], curvey[2], orange)
@@ -156,7 +164,7 @@ func work(screen *ebiten.Image) {
 	}
 
 	// Line
-	yp -= vspace
+	yp = row(7)
 	canvas.Line(objx, yp, objx+hspace, yp+halfvs, linewidth, txcolor)
 	canvas.Circle(objx, yp, dotsize, red)
 	canvas.Circle(objx+hspace, yp+halfvs, dotsize, red)
@@ -167,7 +175,7 @@ func work(screen *ebiten.Image) {
 	canvas.Circle(objx-5, yp, dotsize, red)
 
 	// Polygon
-	yp -= vspace
+	yp = row(8)
 	px := []float32{objx, objx - hspace, objx, objx + hspace}
 	py := []float32{yp + halfvs, yp + 2, yp, yp + 2}
 	for i := 0; i < len(px); i++ {
@@ -177,7 +185,7 @@ func work(screen *ebiten.Image) {
 	canvas.Polygon(px, py, magenta)
 
 	// Image
-	yp -= vspace
+	yp = row(9)
 	canvas.CornerImage(objx-hspace, yp+5, 20, earth)
 	canvas.Image(objx+hspace, yp, 20, earth)
 	canvas.Circle(objx+hspace, yp, dotsize, red)
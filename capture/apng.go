@@ -0,0 +1,135 @@
+package capture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"time"
+)
+
+// pngHeader is the PNG file signature
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// EncodeAPNG writes frames as an animated PNG, with the specified delay between frames.
+// Every frame is stored as 8-bit RGBA, at the size of the first frame.
+func EncodeAPNG(w io.Writer, frames []image.Image, delay time.Duration) error {
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+	b := frames[0].Bounds()
+	width, height := uint32(b.Dx()), uint32(b.Dy())
+	e := &apngWriter{w: w}
+	e.write([]byte(pngHeader))
+
+	// IHDR: 8-bit RGBA, no interlace
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], width)
+	binary.BigEndian.PutUint32(ihdr[4:], height)
+	ihdr[8], ihdr[9] = 8, 6
+	e.chunk("IHDR", ihdr)
+
+	// acTL: number of frames, loop forever
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+	e.chunk("acTL", actl)
+
+	// delay as a fraction of a second: milliseconds, or whole seconds if that does not fit
+	num, den := apngdelay(delay)
+	seq := uint32(0)
+	for i, f := range frames {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], width)
+		binary.BigEndian.PutUint32(fctl[8:], height)
+		binary.BigEndian.PutUint16(fctl[20:], num)
+		binary.BigEndian.PutUint16(fctl[22:], den)
+		e.chunk("fcTL", fctl)
+		seq++
+
+		data, err := compress(f, b)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			e.chunk("IDAT", data)
+			continue
+		}
+		fdat := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, seq)
+		e.chunk("fdAT", append(fdat, data...))
+		seq++
+	}
+	e.chunk("IEND", nil)
+	return e.err
+}
+
+// apngdelay returns the frame delay as the numerator and denominator of a fraction of a second,
+// in milliseconds up to 65.535s, then in seconds up to the largest delay, 65535s
+func apngdelay(d time.Duration) (uint16, uint16) {
+	const most = math.MaxUint16
+	switch ms := d / time.Millisecond; {
+	case ms <= 0:
+		return 0, 1000
+	case ms <= most:
+		return uint16(ms), 1000
+	}
+	return uint16(min(d/time.Second, most)), 1
+}
+
+// compress returns the zlib compressed, unfiltered RGBA rows of the image within bounds r
+func compress(img image.Image, r image.Rectangle) ([]byte, error) {
+	var buf bytes.Buffer
+	z := zlib.NewWriter(&buf)
+	row := make([]byte, 1+4*r.Dx())
+	ib := img.Bounds()
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			c := color.NRGBA{}
+			if image.Pt(ib.Min.X+x, ib.Min.Y+y).In(ib) {
+				c = color.NRGBAModel.Convert(img.At(ib.Min.X+x, ib.Min.Y+y)).(color.NRGBA)
+			}
+			i := 1 + 4*x
+			row[i], row[i+1], row[i+2], row[i+3] = c.R, c.G, c.B, c.A
+		}
+		if _, err := z.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// apngWriter writes PNG chunks, keeping the first error
+type apngWriter struct {
+	w   io.Writer
+	err error
+}
+
+// write writes bytes unless there has been an error
+func (e *apngWriter) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+// chunk writes a PNG chunk: length, type, data and CRC
+func (e *apngWriter) chunk(name string, data []byte) {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(data)))
+	e.write(n[:])
+	e.write([]byte(name))
+	e.write(data)
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(name))
+	crc.Write(data)
+	binary.BigEndian.PutUint32(n[:], crc.Sum32())
+	e.write(n[:])
+}
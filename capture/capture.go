@@ -0,0 +1,178 @@
+// Package capture saves what a canvas draws as PNG images, PNG sequences,
+// animated GIF or animated PNG (APNG)
+package capture
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Format is the output of a recording
+type Format int
+
+const (
+	PNG  Format = iota // numbered PNG files
+	GIF                // animated GIF
+	APNG               // animated PNG
+)
+
+// Snapshot returns a copy of the screen's pixels.
+// Call it at the end of Draw, after the canvas is complete.
+func Snapshot(screen *ebiten.Image) *image.RGBA {
+	b := screen.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	screen.ReadPixels(img.Pix)
+	return img
+}
+
+// SavePNG writes an image to the named PNG file
+func SavePNG(name string, img image.Image) error {
+	w, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(w, img); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// SaveSequence writes frames as numbered PNG files: prefix-0000.png, prefix-0001.png...
+func SaveSequence(prefix string, frames []image.Image) error {
+	for i, f := range frames {
+		if err := SavePNG(fmt.Sprintf("%s-%04d.png", prefix, i), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveGIF writes frames to the named animated GIF file, with the specified delay between frames
+func SaveGIF(name string, frames []image.Image, delay time.Duration) error {
+	w, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := EncodeGIF(w, frames, delay); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// SaveAPNG writes frames to the named animated PNG file, with the specified delay between frames
+func SaveAPNG(name string, frames []image.Image, delay time.Duration) error {
+	w, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := EncodeAPNG(w, frames, delay); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Recorder captures frames from the screen at the end of each Draw.
+// Recording begins when Key is pressed (see Update) or Start is called,
+// and the frames are written once Frames have been captured.
+type Recorder struct {
+	Name       string        // output file (GIF, APNG), or prefix (PNG)
+	Format     Format        // output format
+	Frames     int           // number of frames to record (at least 1)
+	Delay      time.Duration // time between frames in the output
+	Key        ebiten.Key    // key that starts a recording, if KeyBinding is set
+	KeyBinding bool
+	Err        error // error from writing the recording
+
+	frames    []image.Image
+	recording bool
+	done      bool
+}
+
+// NewRecorder makes a recorder of n frames to the named file, inferring the format from its extension:
+// ".gif" for GIF, ".apng" for APNG, otherwise a PNG sequence, using the name without extension as prefix.
+// A recorder captures at least one frame.
+func NewRecorder(name string, n int) *Recorder {
+	n = max(n, 1)
+	r := &Recorder{Name: name, Frames: n, Delay: time.Second / 30}
+	switch filepath.Ext(name) {
+	case ".gif":
+		r.Format = GIF
+	case ".apng":
+		r.Format = APNG
+	default:
+		r.Format = PNG
+		r.Name = name[:len(name)-len(filepath.Ext(name))]
+	}
+	return r
+}
+
+// Bind sets the key that starts a recording
+func (r *Recorder) Bind(key ebiten.Key) *Recorder {
+	r.Key = key
+	r.KeyBinding = true
+	return r
+}
+
+// Start begins recording
+func (r *Recorder) Start() {
+	r.frames = r.frames[:0]
+	r.recording = true
+	r.done = false
+	r.Err = nil
+}
+
+// Recording reports whether frames are being captured
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Done reports whether a recording has been written.
+// Headless captures can return ebiten.Termination from Update once Done is true.
+func (r *Recorder) Done() bool {
+	return r.done
+}
+
+// Update starts a recording when the bound key is pressed; call it from Update
+func (r *Recorder) Update() {
+	if r.KeyBinding && !r.recording && inpututil.IsKeyJustPressed(r.Key) {
+		r.Start()
+	}
+}
+
+// Capture records the screen if recording; call it at the end of Draw
+func (r *Recorder) Capture(screen *ebiten.Image) {
+	if !r.recording {
+		return
+	}
+	r.frames = append(r.frames, Snapshot(screen))
+	if len(r.frames) >= r.Frames {
+		r.recording = false
+		r.Err = r.Write()
+		r.done = true
+	}
+}
+
+// Write saves the captured frames
+func (r *Recorder) Write() error {
+	switch r.Format {
+	case GIF:
+		return SaveGIF(r.Name, r.frames, r.Delay)
+	case APNG:
+		return SaveAPNG(r.Name, r.frames, r.Delay)
+	default:
+		if len(r.frames) == 1 {
+			return SavePNG(r.Name+".png", r.frames[0])
+		}
+		return SaveSequence(r.Name, r.frames)
+	}
+}
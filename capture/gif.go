@@ -0,0 +1,32 @@
+package capture
+
+import (
+	"errors"
+	"image"
+	"image/gif"
+	"io"
+	"time"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// ErrNoFrames is returned when encoding an empty animation
+var ErrNoFrames = errors.New("capture: no frames")
+
+// EncodeGIF writes frames as an animated GIF, with the specified delay between frames.
+// The colors of all frames are quantized, with dithering, to a shared 256 color palette.
+func EncodeGIF(w io.Writer, frames []image.Image, delay time.Duration) error {
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+	pal := ec.ExtractPalette(256, frames...)
+	d := int(delay / (10 * time.Millisecond))
+	anim := &gif.GIF{}
+	for _, f := range frames {
+		p := ec.Quantize(f, pal, true)
+		p.Rect = p.Rect.Sub(p.Rect.Min) // frames start at the origin
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, d)
+	}
+	return gif.EncodeAll(w, anim)
+}
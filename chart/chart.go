@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/scale"
 )
 
 // NameValue is a name,value pair
@@ -28,6 +29,10 @@ type ChartBox struct {
 	Top, Bottom, Left, Right float64
 	Minvalue, Maxvalue       float64
 	Zerobased                bool
+	Patterns                 bool           // give each category of pie and lego charts a pattern, as well as a color
+	ColorScale               *ec.ColorScale // if set, color bars, dots and points by value
+	Palette                  string         // named palette for categories without a color, instead of the theme's
+	Theme                    *ec.Theme      // colors of labels, grids and categories (nil: the light theme)
 }
 
 const (
@@ -38,9 +43,13 @@ const (
 
 const gridlw = 0.075
 
-var labelcolor = color.NRGBA{100, 100, 100, 255}
-var gridcolor = color.NRGBA{128, 128, 128, 128}
-var dottedcolor = color.NRGBA{128, 128, 128, 128}
+// theme returns the theme, or the default light theme
+func theme(t *ec.Theme) *ec.Theme {
+	if t == nil {
+		return &ec.LightTheme
+	}
+	return t
+}
 
 // DataRead reads tab separated values into a ChartBox
 // default values for the top, bottom, left, right (90,50,10,90) are filled in
@@ -107,6 +116,16 @@ func zerobase(usez bool, n float64) float64 {
 	return n
 }
 
+// indexscale maps the positions of the data across the chart
+func (c *ChartBox) indexscale() *scale.Continuous {
+	return scale.NewLinear(0, float64(len(c.Data)-1), c.Left, c.Right)
+}
+
+// valuescale maps data values onto the range from r0 to r1
+func (c *ChartBox) valuescale(r0, r1 float64) *scale.Continuous {
+	return scale.NewLinear(zerobase(c.Zerobased, c.Minvalue), c.Maxvalue, r0, r1)
+}
+
 // drawline makes lines, with special consideration for horizontal and vertical lines
 // by default gio draws lines with round end-caps, this fixes it for straight lines.
 func drawline(canvas *ec.Canvas, x1, y1, x2, y2, sw float32, color color.NRGBA) {
@@ -138,16 +157,23 @@ func (c *ChartBox) MinMax(minv, maxv float64) {
 	c.Maxvalue = maxv
 }
 
+// valuecolor returns the color of a value: from the color scale if there is one
+func (c *ChartBox) valuecolor(v float64) color.NRGBA {
+	if c.ColorScale == nil {
+		return c.Color
+	}
+	return c.ColorScale.Map(v)
+}
+
 // Bar makes a (column) bar chart
 func (c *ChartBox) Bar(canvas *ec.Canvas, size float64) {
-	dlen := float64(len(c.Data) - 1)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	xs, ys := c.indexscale(), c.valuescale(c.Bottom, c.Top)
 	lw := float32(size)
 	bottom := float32(c.Bottom)
 	for i, d := range c.Data {
-		x := float32(ec.MapRange(float64(i), 0, dlen, c.Left, c.Right))
-		y := float32(ec.MapRange(d.value, ymin, c.Maxvalue, c.Bottom, c.Top))
-		drawline(canvas, x, bottom, x, y, lw, c.Color)
+		x := float32(xs.Map(float64(i)))
+		y := float32(ys.Map(d.value))
+		drawline(canvas, x, bottom, x, y, lw, c.valuecolor(d.value))
 	}
 }
 
@@ -158,12 +184,12 @@ func (c *ChartBox) HBar(canvas *ec.Canvas, size, linespacing, textsize float64,
 	ts := float32(textsize)
 	ts3 := ts / 3
 	ls := float32(linespacing)
-	xmin := zerobase(c.Zerobased, c.Minvalue)
+	xs := c.valuescale(c.Left, c.Right)
 	for _, d := range c.Data {
 		ty := y - ts3
-		canvas.EText(cl-2, ty, ts, d.label, labelcolor)
-		x2 := float32(ec.MapRange(d.value, xmin, c.Maxvalue, c.Left, c.Right))
-		drawline(canvas, cl, y, x2, y, float32(size), c.Color)
+		canvas.EText(cl-2, ty, ts, d.label, theme(c.Theme).Label)
+		x2 := float32(xs.Map(d.value))
+		drawline(canvas, cl, y, x2, y, float32(size), c.valuecolor(d.value))
 		if len(valuefmt) > 0 {
 			canvas.Text(x2+ts, ty, ts*0.75, fmt.Sprintf(valuefmt, d.value), ec.ColorLookup(valuecolor))
 		}
@@ -179,13 +205,12 @@ func (c *ChartBox) WBar(canvas *ec.Canvas, linespacing, textsize, opacity float6
 	ts2 := ts / 2
 	ts3 := ts / 3
 	ls := float32(linespacing)
-	xmin := zerobase(c.Zerobased, c.Minvalue)
-	vcolor := c.Color
-	vcolor.A = uint8(255.0 * (opacity / 100))
+	xs := c.valuescale(c.Left, c.Right)
 	for _, d := range c.Data {
 		ty := y - ts3
-		canvas.Text(cl, ty, ts, d.label, labelcolor)
-		x2 := float32(ec.MapRange(d.value, xmin, c.Maxvalue, c.Left, c.Right))
+		canvas.Text(cl, ty, ts, d.label, theme(c.Theme).Label)
+		x2 := float32(xs.Map(d.value))
+		vcolor := ec.WithAlpha(c.valuecolor(d.value), opacity)
 		drawline(canvas, cl, y, x2, y, ts, vcolor)
 		if len(valuefmt) > 0 {
 			canvas.EText(cl-ts2, ty, ts2, fmt.Sprintf(valuefmt, d.value), ec.ColorLookup(valuecolor))
@@ -197,15 +222,14 @@ func (c *ChartBox) WBar(canvas *ec.Canvas, linespacing, textsize, opacity float6
 // Line makes a line chart
 func (c *ChartBox) Line(canvas *ec.Canvas, size float64) {
 	n := len(c.Data)
-	fn := float64(n - 1)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	xs, ys := c.indexscale(), c.valuescale(c.Bottom, c.Top)
 	for i := 0; i < n-1; i++ {
 		v1 := c.Data[i].value
 		v2 := c.Data[i+1].value
-		x1 := float32(ec.MapRange(float64(i), 0, fn, c.Left, c.Right))
-		y1 := float32(ec.MapRange(v1, ymin, c.Maxvalue, c.Bottom, c.Top))
-		x2 := float32(ec.MapRange(float64(i+1), 0, fn, c.Left, c.Right))
-		y2 := float32(ec.MapRange(v2, ymin, c.Maxvalue, c.Bottom, c.Top))
+		x1 := float32(xs.Map(float64(i)))
+		y1 := float32(ys.Map(v1))
+		x2 := float32(xs.Map(float64(i + 1)))
+		y2 := float32(ys.Map(v2))
 		canvas.Line(x1, y1, x2, y2, float32(size), c.Color)
 	}
 }
@@ -213,11 +237,7 @@ func (c *ChartBox) Line(canvas *ec.Canvas, size float64) {
 // Area makes a area chart with specified opacity
 func (c *ChartBox) Area(canvas *ec.Canvas, opacity float64) {
 	n := len(c.Data)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
-	width := c.Right
-	height := c.Top
-	x := c.Left
-	y := c.Bottom
+	xs, ys := c.indexscale(), c.valuescale(c.Bottom, c.Top)
 	ax := make([]float32, n+2)
 	ay := make([]float32, n+2)
 	ax[0] = float32(c.Left)
@@ -225,27 +245,22 @@ func (c *ChartBox) Area(canvas *ec.Canvas, opacity float64) {
 	ax[n+1] = float32(c.Right)
 	ay[n+1] = float32(c.Bottom)
 	for i, d := range c.Data {
-		xp := float32(ec.MapRange(float64(i), 0, float64(n-1), float64(x), float64(width)))
-		yp := float32(ec.MapRange(d.value, ymin, c.Maxvalue, float64(y), float64(height)))
-		ax[i+1] = xp
-		ay[i+1] = yp
+		ax[i+1] = float32(xs.Map(float64(i)))
+		ay[i+1] = float32(ys.Map(d.value))
 	}
-	vcolor := c.Color
-	vcolor.A = uint8(255.0 * (opacity / 100))
-	canvas.Polygon(ax, ay, vcolor)
+	canvas.Polygon(ax, ay, ec.WithAlpha(c.Color, opacity))
 }
 
 // Dot makes a dot chart
 func (c *ChartBox) Dot(canvas *ec.Canvas, size float64) {
-	dlen := float64(len(c.Data) - 1)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	xs, ys := c.indexscale(), c.valuescale(c.Bottom, c.Top)
 	dotsize := float32(size)
 	bottom := float32(c.Bottom)
 	for i, d := range c.Data {
-		x := float32(ec.MapRange(float64(i), 0, dlen, c.Left, c.Right))
-		y := float32(ec.MapRange(d.value, ymin, c.Maxvalue, c.Bottom, c.Top))
-		dottedvline(canvas, x, bottom, y, 0.2, 2, dottedcolor)
-		canvas.Circle(x, y, dotsize, c.Color)
+		x := float32(xs.Map(float64(i)))
+		y := float32(ys.Map(d.value))
+		dottedvline(canvas, x, bottom, y, 0.2, 2, theme(c.Theme).Grid)
+		canvas.Circle(x, y, dotsize, c.valuecolor(d.value))
 	}
 }
 
@@ -258,6 +273,68 @@ func datasum(data []NameValue) float64 {
 	return sum
 }
 
+// patterns are the fills that may follow the color in the note column,
+// in the order they are given to categories when Patterns is set
+var patterns = []string{"solid", "hatch", "dots", "backhatch", "crosshatch", "vstripes", "grid", "hstripes"}
+
+// notefill returns the color and pattern of the i-th category from its note:
+// a color, optionally followed by a pattern name. The pattern has the specified spacing.
+// Without a color, the category takes the next color of the palette, or of the theme.
+func (c *ChartBox) notefill(note string, i int, spacing float32) (color.NRGBA, *ec.Pattern) {
+	name := ""
+	n := strings.LastIndex(note, " ")
+	for _, pn := range patterns {
+		if note[n+1:] == pn {
+			note, name = strings.TrimSpace(note[:n+1]), pn
+			break
+		}
+	}
+	fillcolor := ec.ColorLookup(note)
+	if note == "" {
+		fillcolor = theme(c.Theme).Series(i)
+		if colors, ok := ec.PaletteColors(c.Palette); ok && len(colors) > 0 {
+			fillcolor = colors[i%len(colors)]
+		}
+	}
+	if name == "" && c.Patterns {
+		name = patterns[i%len(patterns)]
+	}
+	var p ec.Pattern
+	size := spacing / 4
+	switch name {
+	case "hatch":
+		p = ec.Hatch(45, spacing, size, fillcolor)
+	case "backhatch":
+		p = ec.Hatch(135, spacing, size, fillcolor)
+	case "crosshatch":
+		p = ec.CrossHatch(45, spacing, size, fillcolor)
+	case "grid":
+		p = ec.CrossHatch(0, spacing, size, fillcolor)
+	case "dots":
+		p = ec.Dots(spacing, spacing/4, fillcolor)
+	case "vstripes":
+		p = ec.Hatch(90, spacing, spacing/2, fillcolor)
+	case "hstripes":
+		p = ec.Hatch(0, spacing, spacing/2, fillcolor)
+	default:
+		return fillcolor, nil
+	}
+	return fillcolor, &p
+}
+
+// fillshape draws a shape in the color; with a pattern,
+// the shape is tinted with the color, and the pattern drawn over it
+func fillshape(canvas *ec.Canvas, fillcolor color.NRGBA, p *ec.Pattern, shape func(*ec.Canvas, color.NRGBA)) {
+	if p == nil {
+		shape(canvas, fillcolor)
+		return
+	}
+	tint := fillcolor
+	tint.A /= 3
+	shape(canvas, tint)
+	canvas.FillPattern(*p, func(m *ec.Canvas) { shape(m, fillcolor) })
+}
+
 // Pie makes a pie chart
 func (c *ChartBox) Pie(canvas *ec.Canvas, r float64) {
 	px, py, pr := float32(c.Left+r), float32(c.Top-r), float32(r)
@@ -265,12 +342,14 @@ func (c *ChartBox) Pie(canvas *ec.Canvas, r float64) {
 	a1 := 0.0
 	labelr := pr + 10
 	ts := pr / 12
-	for _, d := range c.Data {
-		fillcolor := ec.ColorLookup(d.note)
+	for i, d := range c.Data {
+		fillcolor, pattern := c.notefill(d.note, i, pr/10)
 		pct := (d.value / sum)
 		a2 := (fullcircle * pct) + a1
 		mid := (a1 + (a2-a1)/2)
-		canvas.Wedge(px, py, pr, float32(a1), float32(a2), fillcolor)
+		fillshape(canvas, fillcolor, pattern, func(canvas *ec.Canvas, fillcolor color.NRGBA) {
+			canvas.Wedge(px, py, pr, float32(a1), float32(a2), fillcolor)
+		})
 		tx, ty := canvas.PolarDegrees(px, py, labelr, float32(mid))
 		lx, ly := canvas.PolarDegrees(px, py, labelr, float32(mid))
 		canvas.Text(tx, ty, ts, fmt.Sprintf("%s (%.2f%%)", d.label, pct*100), fillcolor)
@@ -279,21 +358,26 @@ func (c *ChartBox) Pie(canvas *ec.Canvas, r float64) {
 	}
 }
 
-// dotgrid makes a grid 10x10 grid of dots colored by value
-func dotgrid(canvas *ec.Canvas, x, y, left, step float32, n int, fillcolor color.NRGBA) (float32, float32) {
+// dotgrid makes a grid 10x10 grid of dots colored by value, with squares filled with the optional pattern
+func dotgrid(canvas *ec.Canvas, x, y, left, step float32, n int, fillcolor color.NRGBA, pattern *ec.Pattern) (float32, float32) {
 	edge := (((step * 0.3) + step) * 7) + left
+	var px, py []float32
 	for i := 0; i < n; i++ {
 		if x > edge {
 			x = left
 			y -= step
 		}
-		op := fillcolor.A
 		canvas.Circle(x, y, step*0.3, fillcolor)
-		fillcolor.A = op - 30
-		canvas.Square(x, y, step*0.9, fillcolor)
-		fillcolor.A = op
+		px, py = append(px, x), append(py, y)
 		x += step
 	}
+	squarecolor := fillcolor
+	squarecolor.A = uint8(max(int(fillcolor.A)-30, 0))
+	fillshape(canvas, squarecolor, pattern, func(canvas *ec.Canvas, fillcolor color.NRGBA) {
+		for i := range px {
+			canvas.Square(px[i], py[i], step*0.9, fillcolor)
+		}
+	})
 	return x, y
 }
 
@@ -305,49 +389,55 @@ func (c *ChartBox) Lego(canvas *ec.Canvas, size float64) {
 	y := float32(c.Top)
 
 	sum := datasum(c.Data)
-	for _, d := range c.Data {
+	for i, d := range c.Data {
 		pct := (d.value / sum) * 100
 		v := int(math.Round(pct))
-		px, py := dotgrid(canvas, x, y, left, step, v, ec.ColorLookup(d.note))
+		fillcolor, pattern := c.notefill(d.note, i, step/3)
+		px, py := dotgrid(canvas, x, y, left, step, v, fillcolor, pattern)
 		x = px
 		y = py
 	}
 	y -= step * 2
-	for _, d := range c.Data {
+	for i, d := range c.Data {
 		pct := (d.value / sum) * 100
 		v := int(math.Round(pct))
-		canvas.Circle(left, y, step*0.3, ec.ColorLookup(d.note))
-		canvas.Text(left+step, y-step*0.2, step*0.5, fmt.Sprintf("%s (%.d%%)", d.label, v), ec.ColorLookup("rgb(120,120,120"))
+		fillcolor, pattern := c.notefill(d.note, i, step/3)
+		if pattern != nil {
+			fillshape(canvas, fillcolor, pattern, func(canvas *ec.Canvas, fillcolor color.NRGBA) {
+				canvas.Square(left, y, step*0.9, fillcolor)
+			})
+		} else {
+			canvas.Circle(left, y, step*0.3, fillcolor)
+		}
+		canvas.Text(left+step, y-step*0.2, step*0.5, fmt.Sprintf("%s (%.d%%)", d.label, v), theme(c.Theme).Label)
 		y -= step
 	}
 }
 
 // Scatter makes a scatter chart
 func (c *ChartBox) Scatter(canvas *ec.Canvas, size float64) {
-	dlen := float64(len(c.Data) - 1)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	xs, ys := c.indexscale(), c.valuescale(c.Bottom, c.Top)
 	dotsize := float32(size)
 	for i, d := range c.Data {
-		x := float32(ec.MapRange(float64(i), 0, dlen, c.Left, c.Right))
-		y := float32(ec.MapRange(d.value, ymin, c.Maxvalue, c.Bottom, c.Top))
-		canvas.Circle(x, y, dotsize, c.Color)
+		x := float32(xs.Map(float64(i)))
+		y := float32(ys.Map(d.value))
+		canvas.Circle(x, y, dotsize, c.valuecolor(d.value))
 	}
 }
 
 // Label draws the x axis and data labels
 func (c *ChartBox) Label(canvas *ec.Canvas, size float64, n int, valuefmt, valuecolor string) {
-	fn := float64(len(c.Data) - 1)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	xs, ys := c.indexscale(), c.valuescale(c.Bottom, c.Top)
 	textsize := float32(size)
 	vsize := float32(size * 0.75)
 	labely := float32(c.Bottom) - (textsize * 2)
 	for i, d := range c.Data {
-		x := float32(ec.MapRange(float64(i), 0, fn, c.Left, c.Right))
+		x := float32(xs.Map(float64(i)))
 		if n > 0 && i%n == 0 {
 			canvas.CText(x, labely, textsize, d.label, c.Color)
 		}
 		if len(valuefmt) > 0 {
-			y := float32(ec.MapRange(d.value, ymin, c.Maxvalue, c.Bottom, c.Top))
+			y := float32(ys.Map(d.value))
 			canvas.CText(x, y+vsize, vsize, fmt.Sprintf(valuefmt, d.value), ec.ColorLookup(valuecolor))
 		}
 	}
@@ -368,12 +458,12 @@ func (c *ChartBox) XAxis(canvas *ec.Canvas, size, min, max, step float64, format
 	bottom := float32(c.Bottom)
 	top := float32(c.Top)
 	textsize := float32(size)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	xs := c.valuescale(c.Left, c.Right)
 	for v := min; v <= max; v += step {
-		x := float32(ec.MapRange(v, ymin, c.Maxvalue, c.Left, c.Right))
+		x := float32(xs.Map(v))
 		canvas.CText(x, bottom, textsize, fmt.Sprintf(format, v), c.Color)
 		if gridlines {
-			drawline(canvas, x, bottom+textsize, x, top+textsize, gridlw, gridcolor)
+			drawline(canvas, x, bottom+textsize, x, top+textsize, gridlw, theme(c.Theme).Grid)
 		}
 	}
 }
@@ -384,12 +474,12 @@ func (c *ChartBox) YAxis(canvas *ec.Canvas, size, min, max, step float64, format
 	textsize := float32(size)
 	ts3 := textsize / 3
 	cl := float32(c.Left)
-	ymin := zerobase(c.Zerobased, c.Minvalue)
+	ys := c.valuescale(c.Bottom, c.Top)
 	for v := min; v <= max; v += step {
-		y := float32(ec.MapRange(v, ymin, c.Maxvalue, c.Bottom, c.Top))
+		y := float32(ys.Map(v))
 		canvas.EText(cl-2, (y - ts3), textsize, fmt.Sprintf(format, v), c.Color)
 		if gridlines {
-			drawline(canvas, cl, y, cl+w, y, gridlw, gridcolor)
+			drawline(canvas, cl, y, cl+w, y, gridlw, theme(c.Theme).Grid)
 		}
 	}
 }
@@ -405,7 +495,6 @@ func (c *ChartBox) Frame(canvas *ec.Canvas, op float64) {
 	if op <= 0 {
 		return
 	}
-	frameColor := c.Color
-	frameColor.A = uint8((op / 100) * 255.0)
+	frameColor := ec.WithAlpha(c.Color, op)
 	canvas.CornerRect(float32(c.Left), float32(c.Top), float32(c.Right-c.Left), float32(c.Top-c.Bottom), frameColor)
 }
@@ -0,0 +1,274 @@
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/scale"
+)
+
+// Plot draws functions in a region of the canvas, mapping the data domain
+// (Xmin-Xmax, Ymin-Ymax) onto the region (Left-Right, Bottom-Top).
+// Functions are sampled adaptively, so curves are smooth where they bend,
+// and broken where they are undefined (NaN or Inf) or discontinuous.
+// Curves are clipped to the region.
+type Plot struct {
+	Left, Right, Bottom, Top float64
+	Xmin, Xmax, Ymin, Ymax   float64
+	Color                    color.NRGBA
+	Tolerance                float64   // maximum distance from the true curve, in percent (default 0.05)
+	Theme                    *ec.Theme // colors of labels and grids (nil: the light theme)
+}
+
+// NewPlot makes a plot of the specified domain, in the default region (10,90,50,90) and color (black)
+func NewPlot(xmin, xmax, ymin, ymax float64) *Plot {
+	return &Plot{
+		Left: 10, Right: 90, Bottom: 50, Top: 90,
+		Xmin: xmin, Xmax: xmax, Ymin: ymin, Ymax: ymax,
+		Color: color.NRGBA{0, 0, 0, 255},
+	}
+}
+
+// sampling parameters
+const (
+	initialSamples = 64
+	maxDepth       = 12
+	jump           = 1.0 // distance (percent) between adjacent samples taken as a discontinuity
+)
+
+// point is a sample of a curve, in canvas coordinates
+type point struct {
+	x, y float64
+	ok   bool
+}
+
+// sampler collects the defined runs of a sampled curve
+type sampler struct {
+	eval func(t float64) point
+	tol  float64
+	runs [][]point
+	cur  []point
+}
+
+// emit adds a sample, ending the current run at undefined points
+func (s *sampler) emit(p point) {
+	if !p.ok {
+		s.cut()
+		return
+	}
+	s.cur = append(s.cur, p)
+}
+
+// cut ends the current run
+func (s *sampler) cut() {
+	if len(s.cur) > 1 {
+		s.runs = append(s.runs, s.cur)
+	}
+	s.cur = nil
+}
+
+// refine adds samples between a and b (exclusive) where the curve bends or breaks
+func (s *sampler) refine(ta float64, a point, tb float64, b point, depth int) {
+	if depth >= maxDepth {
+		if a.ok && b.ok && math.Hypot(b.x-a.x, b.y-a.y) > jump {
+			s.cut()
+		}
+		return
+	}
+	tm := (ta + tb) / 2
+	m := s.eval(tm)
+	switch {
+	case a.ok != b.ok || a.ok != m.ok: // find the edge of the defined region
+	case !a.ok: // undefined throughout
+		return
+	case math.Hypot(m.x-(a.x+b.x)/2, m.y-(a.y+b.y)/2) <= s.tol && math.Hypot(b.x-a.x, b.y-a.y) <= jump*10:
+		return
+	}
+	s.refine(ta, a, tm, m, depth+1)
+	s.emit(m)
+	s.refine(tm, m, tb, b, depth+1)
+}
+
+// sample returns the defined runs of the curve f(t), for t from t0 to t1
+func (p *Plot) sample(f func(t float64) (float64, float64), t0, t1 float64) [][]point {
+	xs := scale.NewLinear(p.Xmin, p.Xmax, p.Left, p.Right)
+	ys := scale.NewLinear(p.Ymin, p.Ymax, p.Bottom, p.Top)
+	s := &sampler{tol: p.Tolerance}
+	if s.tol <= 0 {
+		s.tol = 0.05
+	}
+	s.eval = func(t float64) point {
+		x, y := f(t)
+		if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+			return point{}
+		}
+		return point{x: xs.Map(x), y: ys.Map(y), ok: true}
+	}
+	a := s.eval(t0)
+	s.emit(a)
+	ta := t0
+	for i := 1; i <= initialSamples; i++ {
+		tb := t0 + (t1-t0)*float64(i)/initialSamples
+		b := s.eval(tb)
+		s.refine(ta, a, tb, b, 0)
+		s.emit(b)
+		ta, a = tb, b
+	}
+	s.cut()
+	return s.runs
+}
+
+// clipline clips the line from a to b to the rectangle, using the Liang-Barsky method
+func clipline(a, b point, left, right, bottom, top float64) (point, point, bool) {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b.x-a.x, b.y-a.y
+	edges := [4][2]float64{{-dx, a.x - left}, {dx, right - a.x}, {-dy, a.y - bottom}, {dy, top - a.y}}
+	for _, e := range edges {
+		p, q := e[0], e[1]
+		if p == 0 {
+			if q < 0 {
+				return a, b, false
+			}
+			continue
+		}
+		r := q / p
+		if p < 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+		if t0 > t1 {
+			return a, b, false
+		}
+	}
+	return point{a.x + t0*dx, a.y + t0*dy, true}, point{a.x + t1*dx, a.y + t1*dy, true}, true
+}
+
+// stroke draws the runs, clipped to the region
+func (p *Plot) stroke(canvas *ec.Canvas, runs [][]point, size float64) {
+	var x, y []float32
+	flush := func() {
+		if len(x) > 1 {
+			canvas.Polyline(x, y, float32(size), p.Color)
+		}
+		x, y = nil, nil
+	}
+	for _, run := range runs {
+		for i := 1; i < len(run); i++ {
+			a, b, visible := clipline(run[i-1], run[i], p.Left, p.Right, p.Bottom, p.Top)
+			if !visible {
+				flush()
+				continue
+			}
+			if len(x) == 0 || a != run[i-1] {
+				flush()
+				x, y = append(x, float32(a.x)), append(y, float32(a.y))
+			}
+			x, y = append(x, float32(b.x)), append(y, float32(b.y))
+			if b != run[i] {
+				flush()
+			}
+		}
+		flush()
+	}
+}
+
+// fill fills each run as a polygon, with points limited to the region;
+// if closed is false, the runs are closed along the line y=base
+func (p *Plot) fill(canvas *ec.Canvas, runs [][]point, closed bool, base float64) {
+	by := scale.NewLinear(p.Ymin, p.Ymax, p.Bottom, p.Top)
+	by.Clamp = true
+	basey := by.Map(base)
+	for _, run := range runs {
+		var x, y []float32
+		if !closed {
+			x, y = append(x, float32(clamp(run[0].x, p.Left, p.Right))), append(y, float32(basey))
+		}
+		for _, pt := range run {
+			x = append(x, float32(clamp(pt.x, p.Left, p.Right)))
+			y = append(y, float32(clamp(pt.y, p.Bottom, p.Top)))
+		}
+		if !closed {
+			x, y = append(x, float32(clamp(run[len(run)-1].x, p.Left, p.Right))), append(y, float32(basey))
+		}
+		canvas.Polygon(x, y, p.Color)
+	}
+}
+
+// clamp restricts v to the range lo-hi
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(v, hi))
+}
+
+// Func strokes y=f(x) over the domain
+func (p *Plot) Func(canvas *ec.Canvas, f func(float64) float64, size float64) {
+	p.stroke(canvas, p.sample(func(x float64) (float64, float64) { return x, f(x) }, p.Xmin, p.Xmax), size)
+}
+
+// FillFunc fills the area between y=f(x) and y=base over the domain
+func (p *Plot) FillFunc(canvas *ec.Canvas, f func(float64) float64, base float64) {
+	p.fill(canvas, p.sample(func(x float64) (float64, float64) { return x, f(x) }, p.Xmin, p.Xmax), false, base)
+}
+
+// Parametric strokes the curve (x,y)=f(t), for t from t0 to t1
+func (p *Plot) Parametric(canvas *ec.Canvas, f func(float64) (float64, float64), t0, t1, size float64) {
+	p.stroke(canvas, p.sample(f, t0, t1), size)
+}
+
+// FillParametric fills the closed curve (x,y)=f(t), for t from t0 to t1
+func (p *Plot) FillParametric(canvas *ec.Canvas, f func(float64) (float64, float64), t0, t1 float64) {
+	p.fill(canvas, p.sample(f, t0, t1), true, 0)
+}
+
+// polar converts r=f(theta) into a parametric function
+func polar(f func(float64) float64) func(float64) (float64, float64) {
+	return func(theta float64) (float64, float64) {
+		r := f(theta)
+		return r * math.Cos(theta), r * math.Sin(theta)
+	}
+}
+
+// Polar strokes r=f(theta), for theta (radians) from t0 to t1, centered on the origin of the domain
+func (p *Plot) Polar(canvas *ec.Canvas, f func(float64) float64, t0, t1, size float64) {
+	p.stroke(canvas, p.sample(polar(f), t0, t1), size)
+}
+
+// FillPolar fills the closed curve r=f(theta), for theta (radians) from t0 to t1
+func (p *Plot) FillPolar(canvas *ec.Canvas, f func(float64) float64, t0, t1 float64) {
+	p.fill(canvas, p.sample(polar(f), t0, t1), true, 0)
+}
+
+// Frame makes a filled frame with the specified opacity (0-100)
+func (p *Plot) Frame(canvas *ec.Canvas, op float64) {
+	if op <= 0 {
+		return
+	}
+	frameColor := ec.WithAlpha(p.Color, op)
+	canvas.CornerRect(float32(p.Left), float32(p.Top), float32(p.Right-p.Left), float32(p.Top-p.Bottom), frameColor)
+}
+
+// Axes labels about n round values on the x and y axes, with optional grid lines
+func (p *Plot) Axes(canvas *ec.Canvas, size float64, n int, gridlines bool) {
+	ts := float32(size)
+	left, right := float32(p.Left), float32(p.Right)
+	bottom, top := float32(p.Bottom), float32(p.Top)
+	xs := scale.NewLinear(p.Xmin, p.Xmax, p.Left, p.Right)
+	ys := scale.NewLinear(p.Ymin, p.Ymax, p.Bottom, p.Top)
+	xf, yf := xs.TickFormat(n), ys.TickFormat(n)
+	for _, v := range xs.Ticks(n) {
+		x := float32(xs.Map(v))
+		canvas.CText(x, bottom-ts*2, ts, fmt.Sprintf(xf, v), theme(p.Theme).Label)
+		if gridlines {
+			drawline(canvas, x, bottom, x, top, gridlw, theme(p.Theme).Grid)
+		}
+	}
+	for _, v := range ys.Ticks(n) {
+		y := float32(ys.Map(v))
+		canvas.EText(left-ts, y-ts/3, ts, fmt.Sprintf(yf, v), theme(p.Theme).Label)
+		if gridlines {
+			drawline(canvas, left, y, right, y, gridlw, theme(p.Theme).Grid)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package ebcanvas
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Color formatting: the inverse of ColorLookup
+
+// FormatHex returns the color as "#rrggbb", or "#rrggbbaa" if it is not opaque
+func FormatHex(c color.NRGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// FormatRGB returns the color as "rgb(r,g,b)", or "rgb(r,g,b,a)" with alpha 0-255 if it is not opaque
+func FormatRGB(c color.NRGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf("rgb(%d,%d,%d)", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("rgb(%d,%d,%d,%d)", c.R, c.G, c.B, c.A)
+}
+
+// FormatHSV returns the color as "hsv(h,s,v)", or "hsv(h,s,v,a)" if it is not opaque,
+// with hue 0-360, and saturation, value and alpha 0-100.
+// ColorLookup reads hsv() colors back to within a step or two of each component.
+func FormatHSV(c color.NRGBA) string {
+	h := ToHSV(c)
+	s := "hsv(" + fnum(h.H, 1) + "," + fnum(h.S*100, 1) + "," + fnum(h.V*100, 1)
+	if c.A != 255 {
+		s += "," + fnum(float64(c.A)/2.55, 1)
+	}
+	return s + ")"
+}
+
+// FormatCSS returns the color in CSS syntax: a name if the color has one,
+// otherwise "#rrggbb", or "rgb(r g b / a)" if it is not opaque
+func FormatCSS(c color.NRGBA) string {
+	if name, ok := ColorName(c); ok {
+		return name
+	}
+	if c.A == 255 {
+		return FormatHex(c)
+	}
+	return fmt.Sprintf("rgb(%d %d %d / %s)", c.R, c.G, c.B, fnum(float64(c.A)/255, 3))
+}
+
+// fnum formats a number with at most the number of decimal places, without trailing zeros
+func fnum(v float64, places int) string {
+	p := math.Pow(10, float64(places))
+	v = math.Round(v*p) / p
+	if v == 0 {
+		v = 0 // not -0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// colororder lists the color names in alphabetical order, so that of equivalent names
+// (like "gray" and "grey") the first is chosen
+var colororder []string
+
+func init() {
+	for name := range colornames {
+		colororder = append(colororder, name)
+	}
+	sort.Strings(colororder)
+}
+
+// ColorName returns the SVG name of the color, if it has one
+func ColorName(c color.NRGBA) (string, bool) {
+	for _, name := range colororder {
+		if colornames[name] == c {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// NearestName returns the SVG name of the color nearest c, by distance in OKLab, ignoring alpha
+func NearestName(c color.NRGBA) string {
+	lab := ToOKLab(c)
+	best, dist := "", math.Inf(1)
+	for _, name := range colororder {
+		nc := colornames[name]
+		if nc.A != 255 {
+			continue // transparent
+		}
+		n := ToOKLab(nc)
+		dl, da, db := n.L-lab.L, n.A-lab.A, n.B-lab.B
+		if d := dl*dl + da*da + db*db; d < dist {
+			best, dist = name, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,372 @@
+package ebcanvas
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseColor is like ColorLookup, but reports colors it does not understand,
+// including values out of range, as errors (returning black, as ColorLookup would).
+//
+// Besides the forms of ColorLookup, ParseColor reads CSS Color Level 4 syntax:
+// names in any case, "transparent", "#rgb", "rgb()" and "rgba()" with numbers or percentages,
+// separated by commas or spaces with an optional "/ alpha", "hsl()", "hsla()", "hwb()",
+// and the perceptual "lab()", "lch()", "oklab()" and "oklch()" (out of gamut colors are reduced in chroma).
+// Hues may have the units deg, rad, grad or turn.
+// Colors of named palettes are "name:t", a position 0-1 along the palette, and "name[i]", the i-th color.
+//
+// Where the forms of ColorLookup differ from CSS, they are kept for compatibility:
+// "#rr" and "#rrgg" (rather than "#rgba") set red and green, and the alpha of
+// "rgb(r,g,b,a)" is 0-255 when it is a whole number (rgba() and fractional alpha follow CSS).
+func ParseColor(s string) (color.NRGBA, error) {
+	if c, ok := colornames[s]; ok {
+		return c, nil
+	}
+	black := color.NRGBA{0, 0, 0, 255}
+	ls := strings.ToLower(strings.TrimSpace(s))
+	if c, ok := colornames[ls]; ok {
+		return c, nil
+	}
+	if strings.HasPrefix(ls, "#") {
+		c, err := parsehex(ls[1:])
+		if err != nil {
+			return black, fmt.Errorf("color %q: %v", s, err)
+		}
+		return c, nil
+	}
+	if strings.ContainsAny(ls, ":[") {
+		c, err := parsepalette(ls)
+		if err != nil {
+			return black, fmt.Errorf("color %q: %v", s, err)
+		}
+		return c, nil
+	}
+	open := strings.IndexByte(ls, '(')
+	if open < 0 {
+		return black, fmt.Errorf("color %q: unknown color name", s)
+	}
+	if !strings.HasSuffix(ls, ")") {
+		return black, fmt.Errorf("color %q: missing )", s)
+	}
+	name, args := strings.TrimSpace(ls[:open]), ls[open+1:len(ls)-1]
+	var c color.NRGBA
+	var err error
+	switch name {
+	case "rgb", "rgba":
+		c, err = parsergb(name, args)
+	case "hsl", "hsla", "hwb":
+		c, err = parsehsl(name, args)
+	case "hsv":
+		c, err = parsehsv(args)
+	case "lab", "lch", "oklab", "oklch":
+		c, err = parselab(name, args)
+	default:
+		err = fmt.Errorf("unknown color function %q", name)
+	}
+	if err != nil {
+		return black, fmt.Errorf("color %q: %v", s, err)
+	}
+	return c, nil
+}
+
+// parsehex reads hex digits: rr, rgb, rrgg, rrggbb or rrggbbaa
+func parsehex(digits string) (color.NRGBA, error) {
+	c := color.NRGBA{0, 0, 0, 255}
+	switch len(digits) {
+	case 3: // CSS shorthand: each digit is doubled
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	case 2, 4, 6, 8:
+	default:
+		return c, fmt.Errorf("want 2, 3, 4, 6 or 8 hex digits, have %d", len(digits))
+	}
+	p := []*uint8{&c.R, &c.G, &c.B, &c.A}
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(digits[i:i+2], 16, 8)
+		if err != nil {
+			return color.NRGBA{0, 0, 0, 255}, fmt.Errorf("bad hex digits %q", digits[i:i+2])
+		}
+		*p[i/2] = uint8(v)
+	}
+	return c, nil
+}
+
+// arguments splits the arguments of a color function, separated by commas (the legacy syntax),
+// or by spaces with the alpha after a slash (the modern syntax)
+func arguments(args string) (values []string, alpha string, legacy bool, err error) {
+	if strings.Contains(args, ",") {
+		values = strings.Split(args, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return values, "", true, nil
+	}
+	rest := args
+	if n := strings.IndexByte(args, '/'); n >= 0 {
+		rest, alpha = args[:n], strings.TrimSpace(args[n+1:])
+		if alpha == "" || strings.ContainsAny(alpha, " /") {
+			return nil, "", false, fmt.Errorf("bad alpha %q", alpha)
+		}
+	}
+	return strings.Fields(rest), alpha, false, nil
+}
+
+// number reads a number, or a percentage scaled so 100% is full;
+// the CSS keyword none is zero
+func number(s string, full float64) (float64, bool, error) {
+	if s == "none" {
+		return 0, false, nil
+	}
+	pct := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, pct, fmt.Errorf("bad number %q", s)
+	}
+	if pct {
+		v = v / 100 * full
+	}
+	return v, pct, nil
+}
+
+// parsealpha reads an alpha value: a number 0-1, or a percentage
+func parsealpha(s string) (uint8, error) {
+	v, _, err := number(s, 1)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("alpha %q out of range 0-1 (0%%-100%%)", s)
+	}
+	return uint8(math.Round(v * 255)), nil
+}
+
+// hue reads an angle in degrees, or with the units deg, rad, grad or turn,
+// and returns it in the range 0-360
+func hue(s string) (float64, error) {
+	scale := 1.0
+	for _, u := range []struct {
+		unit  string
+		scale float64
+	}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / math.Pi}, {"turn", 360}} {
+		if strings.HasSuffix(s, u.unit) {
+			s, scale = strings.TrimSuffix(s, u.unit), u.scale
+			break
+		}
+	}
+	v, _, err := number(s, 0)
+	if err != nil || strings.HasSuffix(s, "%") {
+		return 0, fmt.Errorf("bad hue %q", s)
+	}
+	v = math.Mod(v*scale, 360)
+	if v < 0 {
+		v += 360
+	}
+	return v, nil
+}
+
+// parsergb reads the arguments of rgb() and rgba()
+func parsergb(name, args string) (color.NRGBA, error) {
+	c := color.NRGBA{0, 0, 0, 255}
+	values, alpha, legacy, err := arguments(args)
+	if err != nil {
+		return c, err
+	}
+	n := len(values)
+	switch {
+	case name == "rgb" && alpha == "" && (n == 1 || (legacy && n == 2)): // red, or red and green
+	case n == 3:
+	case legacy && n == 4:
+		values, alpha = values[:3], values[3]
+		// a whole number is the 0-255 alpha of ColorLookup
+		if name == "rgb" && !strings.ContainsAny(alpha, ".%") {
+			a, _, err := number(alpha, 0)
+			if err != nil || a < 0 || a > 255 {
+				return c, fmt.Errorf("alpha %q out of range 0-255", alpha)
+			}
+			c.A, alpha = uint8(a), ""
+		}
+	default:
+		return c, fmt.Errorf("want 3 components, have %d", n)
+	}
+	p := []*uint8{&c.R, &c.G, &c.B}
+	for i, s := range values {
+		v, _, err := number(s, 255)
+		if err != nil {
+			return c, err
+		}
+		if v < 0 || v > 255 {
+			return c, fmt.Errorf("component %q out of range 0-255 (0%%-100%%)", s)
+		}
+		*p[i] = uint8(math.Round(v))
+	}
+	if alpha != "" {
+		if c.A, err = parsealpha(alpha); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// parsehsl reads the arguments of hsl(), hsla() and hwb()
+func parsehsl(name, args string) (color.NRGBA, error) {
+	c := color.NRGBA{0, 0, 0, 255}
+	values, alpha, legacy, err := arguments(args)
+	if err != nil {
+		return c, err
+	}
+	if legacy && name == "hwb" {
+		return c, fmt.Errorf("hwb() components are separated by spaces")
+	}
+	if legacy && len(values) == 4 {
+		values, alpha = values[:3], values[3]
+	}
+	if len(values) != 3 {
+		return c, fmt.Errorf("want 3 components, have %d", len(values))
+	}
+	h, err := hue(values[0])
+	if err != nil {
+		return c, err
+	}
+	var v [2]float64
+	for i, s := range values[1:] {
+		f, _, err := number(s, 100)
+		if err != nil {
+			return c, err
+		}
+		if f < 0 || f > 100 {
+			return c, fmt.Errorf("component %q out of range 0%%-100%%", s)
+		}
+		v[i] = f / 100
+	}
+	var r, g, b float64
+	if name == "hwb" {
+		r, g, b = hwb2rgb(h, v[0], v[1])
+	} else {
+		r, g, b = hsl2rgb(h, v[0], v[1])
+	}
+	c.R, c.G, c.B = uint8(math.Round(r*255)), uint8(math.Round(g*255)), uint8(math.Round(b*255))
+	if alpha != "" {
+		if c.A, err = parsealpha(alpha); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// parsehsv reads the arguments of hsv(h,s,v) or hsv(h,s,v,a): h=0-360, s, v, a=0-100
+func parsehsv(args string) (color.NRGBA, error) {
+	c := color.NRGBA{0, 0, 0, 255}
+	values := strings.Split(args, ",")
+	if len(values) != 3 && len(values) != 4 {
+		return c, fmt.Errorf("want 3-4 components, have %d", len(values))
+	}
+	names := []string{"hue", "saturation", "value", "alpha"}
+	v := make([]float64, len(values))
+	for i, s := range values {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || math.IsNaN(f) {
+			return c, fmt.Errorf("bad number %q", strings.TrimSpace(s))
+		}
+		limit := 100.0
+		if i == 0 {
+			limit = 360
+		}
+		if f < 0 || f > limit {
+			return c, fmt.Errorf("%s %v out of range 0-%v", names[i], f, limit)
+		}
+		v[i] = f
+	}
+	c.R, c.G, c.B = hsv2rgb(v[0], v[1], v[2])
+	if len(v) == 4 {
+		c.A = uint8((math.Trunc(v[3]) / 100.0) * 255.0)
+	}
+	return c, nil
+}
+
+// parselab reads the arguments of lab(), lch(), oklab() and oklch(), separated by spaces.
+// Lightness is 0-100 (lab, lch) or 0-1 (oklab, oklch), or a percentage;
+// percentages of the other components are of 125 (lab), 150 (lch) or 0.4 (oklab, oklch).
+func parselab(name, args string) (color.NRGBA, error) {
+	c := color.NRGBA{0, 0, 0, 255}
+	values, alpha, legacy, err := arguments(args)
+	if err != nil {
+		return c, err
+	}
+	if legacy {
+		return c, fmt.Errorf("%s() components are separated by spaces", name)
+	}
+	if len(values) != 3 {
+		return c, fmt.Errorf("want 3 components, have %d", len(values))
+	}
+	lmax, full := 100.0, 125.0
+	switch name {
+	case "lch":
+		full = 150
+	case "oklab", "oklch":
+		lmax, full = 1, 0.4
+	}
+	l, _, err := number(values[0], lmax)
+	if err != nil {
+		return c, err
+	}
+	if l < 0 || l > lmax {
+		return c, fmt.Errorf("lightness %q out of range 0-%v (0%%-100%%)", values[0], lmax)
+	}
+	v1, _, err := number(values[1], full)
+	if err != nil {
+		return c, err
+	}
+	var v2 float64
+	if name == "lch" || name == "oklch" {
+		if v1 < 0 {
+			return c, fmt.Errorf("chroma %q is negative", values[1])
+		}
+		v2, err = hue(values[2])
+	} else {
+		v2, _, err = number(values[2], full)
+	}
+	if err != nil {
+		return c, err
+	}
+	switch name {
+	case "lab":
+		c = Lab{l, v1, v2}.NRGBA()
+	case "lch":
+		c = LCh{l, v1, v2}.NRGBA()
+	case "oklab":
+		c = OKLab{l, v1, v2}.NRGBA()
+	case "oklch":
+		c = OKLCH{l, v1, v2}.NRGBA()
+	}
+	if alpha != "" {
+		if c.A, err = parsealpha(alpha); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// hsl2rgb converts hue (0-360), saturation and lightness (0-1) to rgb (0-1)
+// reference: https://www.w3.org/TR/css-color-4/#hsl-to-rgb
+func hsl2rgb(h, s, l float64) (float64, float64, float64) {
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		return l - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return f(0), f(8), f(4)
+}
+
+// hwb2rgb converts hue (0-360), whiteness and blackness (0-1) to rgb (0-1)
+// reference: https://www.w3.org/TR/css-color-4/#hwb-to-rgb
+func hwb2rgb(h, w, b float64) (float64, float64, float64) {
+	if w+b >= 1 {
+		gray := w / (w + b)
+		return gray, gray, gray
+	}
+	r, g, bl := hsl2rgb(h, 1, 0.5)
+	k := 1 - w - b
+	return r*k + w, g*k + w, bl*k + w
+}
@@ -1,13 +1,14 @@
 package ebcanvas
 
 import (
+	"fmt"
 	"image/color"
 	"math"
 	"strconv"
 	"strings"
 )
 
-// colornames maps SVG color names to RGB triples.
+// colornames maps SVG and CSS color names to RGB triples.
 var colornames = map[string]color.NRGBA{
 	"aliceblue":            {240, 248, 255, 255},
 	"antiquewhite":         {250, 235, 215, 255},
@@ -128,6 +129,7 @@ var colornames = map[string]color.NRGBA{
 	"plum":                 {221, 160, 221, 255},
 	"powderblue":           {176, 224, 230, 255},
 	"purple":               {128, 0, 128, 255},
+	"rebeccapurple":        {102, 51, 153, 255},
 	"red":                  {255, 0, 0, 255},
 	"rosybrown":            {188, 143, 143, 255},
 	"royalblue":            {65, 105, 225, 255},
@@ -149,6 +151,7 @@ var colornames = map[string]color.NRGBA{
 	"teal":                 {0, 128, 128, 255},
 	"thistle":              {216, 191, 216, 255},
 	"tomato":               {255, 99, 71, 255},
+	"transparent":          {0, 0, 0, 0},
 	"turquoise":            {64, 224, 208, 255},
 	"violet":               {238, 130, 238, 255},
 	"wheat":                {245, 222, 179, 255},
@@ -158,113 +161,62 @@ var colornames = map[string]color.NRGBA{
 	"yellowgreen":          {154, 205, 50, 255},
 }
 
-// cc converts a color string to number
-func cc(s string) uint8 {
-	v, err := strconv.Atoi(s)
-	if err != nil {
-		return 0
-	}
-	return uint8(v)
-}
-
-// hc converts a hex color string to number
-func hc(s string) uint8 {
-	v, err := strconv.ParseInt(s, 16, 32)
-	if err != nil {
-		return 0
-	}
-	return uint8(v)
-}
-
 // ColorLookup returns a color.NRGBA corresponding to the named color or
 // "rgb(r)", "rgb(r,b)", "rgb(r,g,b), "rgb(r,g,b,a)",
 // "#rr",     "#rrgg",   "#rrggbb",   "#rrggbbaa" string.
-// "hsv(hue,sat,value)"
-// On error, return black.
+// "hsv(hue,sat,value)", or CSS color (see ParseColor).
+// Out of range rgb and hsv components are clamped (rgb 0-255; saturation, value and alpha 0-100),
+// and hues taken modulo 360. On error, return black.
 func ColorLookup(s string) color.NRGBA {
-	c, ok := colornames[s]
-	if ok {
-		return c
+	c, err := ParseColor(s)
+	if err != nil {
+		c, _ = clampcolor(s)
 	}
+	return c
+}
+
+// clampcolor reads rgb(...) and hsv(...) colors with comma separated components,
+// bringing components that are out of range into range
+func clampcolor(s string) (color.NRGBA, error) {
 	black := color.NRGBA{0, 0, 0, 255}
-	ls := len(s)
-	// rgb(...)
-	if strings.HasPrefix(s, "rgb(") && strings.HasSuffix(s, ")") && ls > 5 {
-		c.R, c.G, c.B, c.A = 0, 0, 0, 255
-		v := strings.Split(s[4:ls-1], ",")
-		switch len(v) {
-		case 1:
-			c.R = cc(v[0])
-			return c
-		case 2:
-			c.R = cc(v[0])
-			c.G = cc(v[1])
-			return c
-		case 3:
-			c.R = cc(v[0])
-			c.G = cc(v[1])
-			c.B = cc(v[2])
-			return c
-		case 4:
-			c.R = cc(v[0])
-			c.G = cc(v[1])
-			c.B = cc(v[2])
-			c.A = cc(v[3])
-			return c
-		default:
-			return black
-		}
+	ls := strings.ToLower(strings.TrimSpace(s))
+	open := strings.IndexByte(ls, '(')
+	if open < 0 || !strings.HasSuffix(ls, ")") {
+		return black, fmt.Errorf("color %q: not rgb() or hsv()", s)
+	}
+	name, args := strings.TrimSpace(ls[:open]), strings.Split(ls[open+1:len(ls)-1], ",")
+	if name != "rgb" && name != "hsv" {
+		return black, fmt.Errorf("color %q: not rgb() or hsv()", s)
 	}
-	// hsv(h,s,v) or hsv(h,s,v,a); h=0-360, s, v=0-100, a=0-100
-	if strings.HasPrefix(s, "hsv(") && strings.HasSuffix(s, ")") && ls > 5 {
-		v := strings.Split(s[4:ls-1], ",")
-		switch len(v) {
-		case 3:
-			hue, _ := strconv.ParseFloat(v[0], 64)
-			sat, _ := strconv.ParseFloat(v[1], 64)
-			value, _ := strconv.ParseFloat(v[2], 64)
-			c.R, c.G, c.B = hsv2rgb(hue, sat, value)
-			c.A = 255
-			return c
-		case 4:
-			hue, _ := strconv.ParseFloat(v[0], 64)
-			sat, _ := strconv.ParseFloat(v[1], 64)
-			value, _ := strconv.ParseFloat(v[2], 64)
-			a := cc(v[3])
-			if a > 100 {
-				a = 100
+	for i, a := range args {
+		a = strings.TrimSpace(a)
+		pct := strings.HasSuffix(a, "%")
+		v, err := strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+		if err != nil || math.IsNaN(v) {
+			return black, fmt.Errorf("color %q: bad number %q", s, a)
+		}
+		switch {
+		case name == "hsv" && i == 0:
+			v = math.Mod(v, 360)
+			if v < 0 {
+				v += 360
 			}
-			c.R, c.G, c.B = hsv2rgb(hue, sat, value)
-			c.A = uint8((float64(a) / 100.0) * 255.0)
-			return c
+		case name == "hsv", pct:
+			v = clamp(v, 0, 100)
+		case i == 3 && strings.Contains(a, "."): // alpha 0-1
+			v = clamp(v, 0, 1)
 		default:
-			return black
+			v = clamp(v, 0, 255)
 		}
-	}
-	// #rrggbb
-	if strings.HasPrefix(s, "#") && (ls >= 3) {
-		c.R, c.G, c.B, c.A = 0, 0, 0, 255
-		switch ls {
-		case 3:
-			c.R = hc(s[1:3])
-		case 5:
-			c.R = hc(s[1:3])
-			c.G = hc(s[3:5])
-		case 7:
-			c.R = hc(s[1:3])
-			c.G = hc(s[3:5])
-			c.B = hc(s[5:7])
-		case 9:
-			c.R = hc(s[1:3])
-			c.G = hc(s[3:5])
-			c.B = hc(s[5:7])
-			c.A = hc(s[7:9])
-		default:
-			return black
+		args[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		if strings.Contains(a, ".") && !strings.Contains(args[i], ".") {
+			args[i] += ".0" // keep a 0-1 alpha from reading as 0-255
+		}
+		if pct {
+			args[i] += "%"
 		}
-		return c
 	}
-	return black
+	return ParseColor(name + "(" + strings.Join(args, ",") + ")")
 }
 
 // hsv2rgb converts hsv(h (0-360), s (0-100), v (0-100)) to rgb
@@ -315,3 +267,118 @@ func hsv2rgb(h, s, v float64) (uint8, uint8, uint8) {
 	b += m
 	return uint8(r * 255), uint8(g * 255), uint8(b * 255)
 }
+
+// clamp limits v to lo-hi
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// Lighten raises the lightness of a color by pct percent (in OKLCH), up to white
+func Lighten(c color.NRGBA, pct float64) color.NRGBA {
+	l := ToOKLCH(c)
+	l.L = clamp(l.L+pct/100, 0, 1)
+	r := l.NRGBA()
+	r.A = c.A
+	return r
+}
+
+// Darken lowers the lightness of a color by pct percent (in OKLCH), down to black
+func Darken(c color.NRGBA, pct float64) color.NRGBA {
+	return Lighten(c, -pct)
+}
+
+// Saturate raises the chroma of a color by pct percent (in OKLCH, where 100% is chroma 0.4);
+// a negative pct desaturates, down to gray. Grays are unchanged, having no hue.
+func Saturate(c color.NRGBA, pct float64) color.NRGBA {
+	l := ToOKLCH(c)
+	if l.C < 1e-4 {
+		return c
+	}
+	l.C = math.Max(0, l.C+pct/100*0.4)
+	r := l.NRGBA()
+	r.A = c.A
+	return r
+}
+
+// Mix returns the color a fraction t (0-1) of the way from a to b, in OKLab
+func Mix(a, b color.NRGBA, t float64) color.NRGBA {
+	return Interpolate(a, b, clamp(t, 0, 1), InOKLab)
+}
+
+// WithAlpha returns the color with opacity pct percent (0-100)
+func WithAlpha(c color.NRGBA, pct float64) color.NRGBA {
+	c.A = uint8(math.Round(clamp(pct, 0, 100) / 100 * 255))
+	return c
+}
+
+// Invert returns the negative of a color, keeping its alpha
+func Invert(c color.NRGBA) color.NRGBA {
+	return color.NRGBA{255 - c.R, 255 - c.G, 255 - c.B, c.A}
+}
+
+// Complement returns the color with the opposite hue (in HSL), keeping its alpha
+func Complement(c color.NRGBA) color.NRGBA {
+	h := ToHSL(c)
+	h.H = math.Mod(h.H+180, 360)
+	r := h.NRGBA()
+	r.A = c.A
+	return r
+}
+
+// CompositeOp is a Porter-Duff compositing operator
+type CompositeOp int
+
+// Porter-Duff operators, combining a source color with a destination color
+const (
+	Clear   CompositeOp = iota // neither
+	Src                        // the source only
+	Dst                        // the destination only
+	Over                       // the source over the destination
+	DstOver                    // the destination over the source
+	In                         // the source where the destination is
+	DstIn                      // the destination where the source is
+	Out                        // the source where the destination is not
+	DstOut                     // the destination where the source is not
+	Atop                       // the source over the destination, where the destination is
+	DstAtop                    // the destination over the source, where the source is
+	Xor                        // the source and destination where the other is not
+)
+
+// Composite combines src with dst using the Porter-Duff operator
+func Composite(src, dst color.NRGBA, op CompositeOp) color.NRGBA {
+	as, ad := float64(src.A)/255, float64(dst.A)/255
+	var fs, fd float64 // the fractions of source and destination
+	switch op {
+	case Src:
+		fs, fd = 1, 0
+	case Dst:
+		fs, fd = 0, 1
+	case Over:
+		fs, fd = 1, 1-as
+	case DstOver:
+		fs, fd = 1-ad, 1
+	case In:
+		fs, fd = ad, 0
+	case DstIn:
+		fs, fd = 0, as
+	case Out:
+		fs, fd = 1-ad, 0
+	case DstOut:
+		fs, fd = 0, 1-as
+	case Atop:
+		fs, fd = ad, 1-as
+	case DstAtop:
+		fs, fd = 1-ad, as
+	case Xor:
+		fs, fd = 1-ad, 1-as
+	}
+	a := fs*as + fd*ad
+	if a <= 0 {
+		return color.NRGBA{}
+	}
+	// blend premultiplied components, then divide by the result alpha
+	mix := func(s, d uint8) uint8 {
+		return uint8(math.Round(clamp((fs*as*float64(s)+fd*ad*float64(d))/a, 0, 255)))
+	}
+	return color.NRGBA{mix(src.R, dst.R), mix(src.G, dst.G), mix(src.B, dst.B), uint8(math.Round(a * 255))}
+}
@@ -0,0 +1,188 @@
+package ebcanvas
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Color scales: mapping numbers and categories to colors
+
+// ColorSpace is the space in which colors are interpolated
+type ColorSpace int
+
+const (
+	InRGB       ColorSpace = iota // sRGB components
+	InLinearRGB                   // linear light, as lights mix
+	InHSV                         // hue, saturation and value, hue the short way round
+	InLab                         // CIE Lab
+	InOKLab                       // OKLab: even steps of lightness
+	InOKLCH                       // OKLCH: even steps of lightness, hue the short way round
+)
+
+// lerp interpolates between a and b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// lerphue interpolates between hues (degrees) the short way round;
+// an undefined hue (that of a gray) takes the other
+func lerphue(a, b, t float64, agray, bgray bool) float64 {
+	switch {
+	case agray && bgray:
+		return 0
+	case agray:
+		return b
+	case bgray:
+		return a
+	}
+	d := math.Mod(b-a+540, 360) - 180
+	h := math.Mod(a+d*t, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// Interpolate returns the color a fraction t (0-1) of the way from a to b in the color space;
+// alpha is interpolated linearly
+func Interpolate(a, b color.NRGBA, t float64, space ColorSpace) color.NRGBA {
+	var c color.NRGBA
+	switch space {
+	case InLinearRGB:
+		la, lb := ToLinear(a), ToLinear(b)
+		c = LinearRGB{lerp(la.R, lb.R, t), lerp(la.G, lb.G, t), lerp(la.B, lb.B, t)}.NRGBA()
+	case InHSV:
+		ha, hb := ToHSV(a), ToHSV(b)
+		c = HSV{lerphue(ha.H, hb.H, t, ha.S == 0, hb.S == 0), lerp(ha.S, hb.S, t), lerp(ha.V, hb.V, t)}.NRGBA()
+	case InLab:
+		la, lb := ToLab(a), ToLab(b)
+		c = Lab{lerp(la.L, lb.L, t), lerp(la.A, lb.A, t), lerp(la.B, lb.B, t)}.NRGBA()
+	case InOKLab:
+		oa, ob := ToOKLab(a), ToOKLab(b)
+		c = OKLab{lerp(oa.L, ob.L, t), lerp(oa.A, ob.A, t), lerp(oa.B, ob.B, t)}.NRGBA()
+	case InOKLCH:
+		const gray = 1e-4
+		oa, ob := ToOKLCH(a), ToOKLCH(b)
+		c = OKLCH{lerp(oa.L, ob.L, t), lerp(oa.C, ob.C, t), lerphue(oa.H, ob.H, t, oa.C < gray, ob.C < gray)}.NRGBA()
+	default:
+		c = color.NRGBA{
+			uint8(math.Round(lerp(float64(a.R), float64(b.R), t))),
+			uint8(math.Round(lerp(float64(a.G), float64(b.G), t))),
+			uint8(math.Round(lerp(float64(a.B), float64(b.B), t))),
+			255,
+		}
+	}
+	c.A = uint8(math.Round(lerp(float64(a.A), float64(b.A), t)))
+	return c
+}
+
+// ColorScale maps numbers to colors, interpolating between colors at increasing stops.
+// Values beyond the first and last stops take the end colors; NaN (missing data) maps to Missing.
+// If Classes is more than zero, the output is limited to that many colors, evenly spaced,
+// as for the classes of a choropleth map.
+type ColorScale struct {
+	Stops   []float64
+	Colors  []color.NRGBA
+	Space   ColorSpace
+	Classes int
+	Missing color.NRGBA
+}
+
+// NewSequential makes a scale from lo to hi through the colors, evenly spaced,
+// interpolated in OKLab
+func NewSequential(lo, hi float64, colors ...color.NRGBA) *ColorScale {
+	s := &ColorScale{Colors: colors, Space: InOKLab}
+	s.Stops = make([]float64, len(colors))
+	for i := range colors {
+		s.Stops[i] = lo
+		if len(colors) > 1 {
+			s.Stops[i] = lerp(lo, hi, float64(i)/float64(len(colors)-1))
+		}
+	}
+	return s
+}
+
+// NewDiverging makes a scale from the low color at lo, through the middle color at mid,
+// to the high color at hi, interpolated in OKLab;
+// use it for values above and below a midpoint such as zero or an average
+func NewDiverging(lo, mid, hi float64, low, middle, high color.NRGBA) *ColorScale {
+	return &ColorScale{
+		Stops:  []float64{lo, mid, hi},
+		Colors: []color.NRGBA{low, middle, high},
+		Space:  InOKLab,
+	}
+}
+
+// Map returns the color for the value
+func (s *ColorScale) Map(v float64) color.NRGBA {
+	n := min(len(s.Stops), len(s.Colors))
+	if n == 0 || math.IsNaN(v) {
+		return s.Missing
+	}
+	lo, hi := s.Stops[0], s.Stops[n-1]
+	if s.Classes > 0 && hi > lo {
+		// the middle of the class containing v
+		k := float64(s.Classes)
+		i := math.Min(k-1, math.Max(0, math.Floor((v-lo)/(hi-lo)*k)))
+		v = lo + (i+0.5)/k*(hi-lo)
+	}
+	if v <= lo {
+		return s.Colors[0]
+	}
+	if v >= hi {
+		return s.Colors[n-1]
+	}
+	i := sort.SearchFloat64s(s.Stops[:n], v) // Stops[i-1] < v <= Stops[i]
+	a, b := s.Stops[i-1], s.Stops[i]
+	return Interpolate(s.Colors[i-1], s.Colors[i], (v-a)/(b-a), s.Space)
+}
+
+// Sample returns n colors evenly spaced over the scale, for legends and palettes
+func (s *ColorScale) Sample(n int) []color.NRGBA {
+	m := min(len(s.Stops), len(s.Colors))
+	if n <= 0 || m == 0 {
+		return nil
+	}
+	lo, hi := s.Stops[0], s.Stops[m-1]
+	colors := make([]color.NRGBA, n)
+	for i := range colors {
+		t := 0.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		colors[i] = s.Map(lerp(lo, hi, t))
+	}
+	return colors
+}
+
+// Categorical maps categories to colors, in the order the categories are first seen.
+// Categories not in the domain are added to it; colors are reused when there are more categories than colors.
+type Categorical struct {
+	Domain []string
+	Colors []color.NRGBA
+}
+
+// NewCategorical makes a categorical scale
+func NewCategorical(colors ...color.NRGBA) *Categorical {
+	return &Categorical{Colors: colors}
+}
+
+// Map returns the color for the category
+func (c *Categorical) Map(v string) color.NRGBA {
+	if len(c.Colors) == 0 {
+		return color.NRGBA{}
+	}
+	i := -1
+	for j, d := range c.Domain {
+		if d == v {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		c.Domain = append(c.Domain, v)
+		i = len(c.Domain) - 1
+	}
+	return c.Colors[i%len(c.Colors)]
+}
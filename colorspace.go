@@ -0,0 +1,336 @@
+package ebcanvas
+
+import (
+	"image/color"
+	"math"
+)
+
+// Color spaces: conversions between sRGB (color.NRGBA) and linear RGB, HSL, HSV,
+// CIE Lab and LCh (D50, as in CSS), and OKLab and OKLCH.
+// Perceptual spaces (Lab, LCh, OKLab, OKLCH) have even steps of perceived lightness,
+// so they are good for making palettes and interpolating colors.
+// Conversions to color.NRGBA are opaque; set the alpha afterwards.
+
+// LinearRGB is a color in linear-light sRGB: components range from 0-1
+type LinearRGB struct{ R, G, B float64 }
+
+// HSL is a color as hue (0-360), saturation and lightness (0-1)
+type HSL struct{ H, S, L float64 }
+
+// HSV is a color as hue (0-360), saturation and value (0-1)
+type HSV struct{ H, S, V float64 }
+
+// Lab is a color in CIE Lab: lightness L (0-100), and a and b (about -125 to 125)
+type Lab struct{ L, A, B float64 }
+
+// LCh is a color in CIE LCh: lightness L (0-100), chroma C (0 to about 150) and hue H (0-360)
+type LCh struct{ L, C, H float64 }
+
+// OKLab is a color in OKLab: lightness L (0-1), and a and b (about -0.4 to 0.4)
+type OKLab struct{ L, A, B float64 }
+
+// OKLCH is a color in OKLCH: lightness L (0-1), chroma C (0 to about 0.4) and hue H (0-360)
+type OKLCH struct{ L, C, H float64 }
+
+// tolinear converts an sRGB component (0-1) to linear light
+func tolinear(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Copysign(math.Pow((a+0.055)/1.055, 2.4), v)
+}
+
+// togamma converts a linear light component to sRGB (0-1)
+func togamma(v float64) float64 {
+	a := math.Abs(v)
+	if a <= 0.0031308 {
+		return v * 12.92
+	}
+	return math.Copysign(1.055*math.Pow(a, 1/2.4)-0.055, v)
+}
+
+// tobyte converts a component (0-1) to 0-255, clipping
+func tobyte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// ToLinear converts a color to linear RGB
+func ToLinear(c color.NRGBA) LinearRGB {
+	return LinearRGB{tolinear(float64(c.R) / 255), tolinear(float64(c.G) / 255), tolinear(float64(c.B) / 255)}
+}
+
+// NRGBA converts linear RGB to sRGB, clipping components outside 0-1
+func (l LinearRGB) NRGBA() color.NRGBA {
+	return color.NRGBA{tobyte(togamma(l.R)), tobyte(togamma(l.G)), tobyte(togamma(l.B)), 255}
+}
+
+// InGamut reports whether the color can be shown in sRGB without clipping
+func (l LinearRGB) InGamut() bool {
+	const e = 1e-6
+	return l.R >= -e && l.R <= 1+e && l.G >= -e && l.G <= 1+e && l.B >= -e && l.B <= 1+e
+}
+
+// rgbhue returns the hue (0-360), and the largest and smallest of the components
+func rgbhue(r, g, b float64) (float64, float64, float64) {
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	d := hi - lo
+	var h float64
+	switch {
+	case d == 0:
+		h = 0
+	case hi == r:
+		h = math.Mod((g-b)/d, 6)
+	case hi == g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, hi, lo
+}
+
+// ToHSL converts a color to HSL
+func ToHSL(c color.NRGBA) HSL {
+	h, hi, lo := rgbhue(float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+	l := (hi + lo) / 2
+	s := 0.0
+	if d := hi - lo; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+	}
+	return HSL{h, s, l}
+}
+
+// NRGBA converts HSL to sRGB
+func (h HSL) NRGBA() color.NRGBA {
+	r, g, b := hsl2rgb(math.Mod(h.H, 360), h.S, h.L)
+	return color.NRGBA{tobyte(r), tobyte(g), tobyte(b), 255}
+}
+
+// ToHSV converts a color to HSV
+func ToHSV(c color.NRGBA) HSV {
+	h, hi, lo := rgbhue(float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+	s := 0.0
+	if hi > 0 {
+		s = (hi - lo) / hi
+	}
+	return HSV{h, s, hi}
+}
+
+// NRGBA converts HSV to sRGB
+func (h HSV) NRGBA() color.NRGBA {
+	// the hsv() color function, but with fractions, and rounding
+	c := h.V * h.S
+	hue := math.Mod(h.H, 360)
+	if hue < 0 {
+		hue += 360
+	}
+	f := func(n float64) float64 {
+		k := math.Mod(n+hue/60, 6)
+		return h.V - c*math.Max(0, math.Min(math.Min(k, 4-k), 1))
+	}
+	return color.NRGBA{tobyte(f(5)), tobyte(f(3)), tobyte(f(1)), 255}
+}
+
+// mul multiplies a 3x3 matrix and a vector
+func mul(m *[3][3]float64, x, y, z float64) (float64, float64, float64) {
+	return m[0][0]*x + m[0][1]*y + m[0][2]*z,
+		m[1][0]*x + m[1][1]*y + m[1][2]*z,
+		m[2][0]*x + m[2][1]*y + m[2][2]*z
+}
+
+// matrices from CSS Color Level 4: linear sRGB to XYZ (D65), XYZ to linear sRGB,
+// and the Bradford chromatic adaptation between D65 and D50
+var (
+	srgbToXYZ = [3][3]float64{
+		{506752.0 / 1228815, 87881.0 / 245763, 12673.0 / 70218},
+		{87098.0 / 409605, 175762.0 / 245763, 12673.0 / 175545},
+		{7918.0 / 409605, 87881.0 / 737289, 1001167.0 / 1053270},
+	}
+	xyzToSRGB = [3][3]float64{
+		{12831.0 / 3959, -329.0 / 214, -1974.0 / 3959},
+		{-851781.0 / 878810, 1648619.0 / 878810, 36519.0 / 878810},
+		{705.0 / 12673, -2585.0 / 12673, 705.0 / 667},
+	}
+	d65ToD50 = [3][3]float64{
+		{1.0479297925449969, 0.022946870601609652, -0.05019226628920524},
+		{0.02962780877005599, 0.9904344267538799, -0.017073799063418826},
+		{-0.009243040646204504, 0.015055191490298152, 0.7518742814281371},
+	}
+	d50ToD65 = [3][3]float64{
+		{0.955473421488075, -0.02309845494876471, 0.06325924320057072},
+		{-0.0283697093338637, 1.0099953980813041, 0.021041441191917323},
+		{0.012314014864481998, -0.020507649298898964, 1.330365926242124},
+	}
+	d50White = [3]float64{0.3457 / 0.3585, 1, (1 - 0.3457 - 0.3585) / 0.3585}
+)
+
+// CIE Lab constants
+const (
+	labEpsilon = 216.0 / 24389
+	labKappa   = 24389.0 / 27
+)
+
+// Lab converts linear RGB to CIE Lab
+func (l LinearRGB) Lab() Lab {
+	x, y, z := mul(&srgbToXYZ, l.R, l.G, l.B)
+	x, y, z = mul(&d65ToD50, x, y, z)
+	f := func(v float64) float64 {
+		if v > labEpsilon {
+			return math.Cbrt(v)
+		}
+		return (labKappa*v + 16) / 116
+	}
+	fx, fy, fz := f(x/d50White[0]), f(y/d50White[1]), f(z/d50White[2])
+	return Lab{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// Linear converts CIE Lab to linear RGB
+func (c Lab) Linear() LinearRGB {
+	fy := (c.L + 16) / 116
+	fx := c.A/500 + fy
+	fz := fy - c.B/200
+	finv := func(f float64) float64 {
+		if f*f*f > labEpsilon {
+			return f * f * f
+		}
+		return (116*f - 16) / labKappa
+	}
+	y := c.L / labKappa
+	if c.L > labKappa*labEpsilon {
+		y = fy * fy * fy
+	}
+	x, z := finv(fx)*d50White[0], finv(fz)*d50White[2]
+	x, y, z = mul(&d50ToD65, x, y*d50White[1], z)
+	r, g, b := mul(&xyzToSRGB, x, y, z)
+	return LinearRGB{r, g, b}
+}
+
+// ToLab converts a color to CIE Lab
+func ToLab(c color.NRGBA) Lab {
+	return ToLinear(c).Lab()
+}
+
+// NRGBA converts CIE Lab to sRGB, clipping colors outside the sRGB gamut
+func (c Lab) NRGBA() color.NRGBA {
+	return c.Linear().NRGBA()
+}
+
+// polar converts rectangular a, b to chroma and hue (0-360)
+func polar(a, b float64) (float64, float64) {
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return math.Hypot(a, b), h
+}
+
+// rect converts chroma and hue (degrees) to rectangular a, b
+func rect(c, h float64) (float64, float64) {
+	s, co := math.Sincos(h * math.Pi / 180)
+	return c * co, c * s
+}
+
+// LCh converts CIE Lab to LCh
+func (c Lab) LCh() LCh {
+	ch, h := polar(c.A, c.B)
+	return LCh{c.L, ch, h}
+}
+
+// Lab converts CIE LCh to Lab
+func (c LCh) Lab() Lab {
+	a, b := rect(c.C, c.H)
+	return Lab{c.L, a, b}
+}
+
+// ToLCh converts a color to CIE LCh
+func ToLCh(c color.NRGBA) LCh {
+	return ToLab(c).LCh()
+}
+
+// NRGBA converts CIE LCh to sRGB; colors outside the sRGB gamut
+// keep their lightness and hue, with chroma reduced to fit
+func (c LCh) NRGBA() color.NRGBA {
+	return gamutmap(c.C, func(ch float64) LinearRGB { return LCh{c.L, ch, c.H}.Lab().Linear() })
+}
+
+// OKLab converts linear RGB to OKLab
+// reference: https://bottosson.github.io/posts/oklab/
+func (l LinearRGB) OKLab() OKLab {
+	lc := math.Cbrt(0.4122214708*l.R + 0.5363325363*l.G + 0.0514459929*l.B)
+	mc := math.Cbrt(0.2119034982*l.R + 0.6806995451*l.G + 0.1073969566*l.B)
+	sc := math.Cbrt(0.0883024619*l.R + 0.2817188376*l.G + 0.6299787005*l.B)
+	return OKLab{
+		0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc,
+		1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc,
+		0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc,
+	}
+}
+
+// Linear converts OKLab to linear RGB
+func (c OKLab) Linear() LinearRGB {
+	lc := c.L + 0.3963377774*c.A + 0.2158037573*c.B
+	mc := c.L - 0.1055613458*c.A - 0.0638541728*c.B
+	sc := c.L - 0.0894841775*c.A - 1.2914855480*c.B
+	l, m, s := lc*lc*lc, mc*mc*mc, sc*sc*sc
+	return LinearRGB{
+		+4.0767416621*l - 3.3077115913*m + 0.2309699292*s,
+		-1.2684380046*l + 2.6097574011*m - 0.3413193965*s,
+		-0.0041960863*l - 0.7034186147*m + 1.7076147010*s,
+	}
+}
+
+// ToOKLab converts a color to OKLab
+func ToOKLab(c color.NRGBA) OKLab {
+	return ToLinear(c).OKLab()
+}
+
+// NRGBA converts OKLab to sRGB, clipping colors outside the sRGB gamut
+func (c OKLab) NRGBA() color.NRGBA {
+	return c.Linear().NRGBA()
+}
+
+// OKLCH converts OKLab to OKLCH
+func (c OKLab) OKLCH() OKLCH {
+	ch, h := polar(c.A, c.B)
+	return OKLCH{c.L, ch, h}
+}
+
+// OKLab converts OKLCH to OKLab
+func (c OKLCH) OKLab() OKLab {
+	a, b := rect(c.C, c.H)
+	return OKLab{c.L, a, b}
+}
+
+// ToOKLCH converts a color to OKLCH
+func ToOKLCH(c color.NRGBA) OKLCH {
+	return ToOKLab(c).OKLCH()
+}
+
+// NRGBA converts OKLCH to sRGB; colors outside the sRGB gamut
+// keep their lightness and hue, with chroma reduced to fit
+func (c OKLCH) NRGBA() color.NRGBA {
+	return gamutmap(c.C, func(ch float64) LinearRGB { return OKLCH{c.L, ch, c.H}.OKLab().Linear() })
+}
+
+// gamutmap finds the largest chroma, up to c, for which the color is in the sRGB gamut,
+// and returns that color
+func gamutmap(c float64, at func(chroma float64) LinearRGB) color.NRGBA {
+	l := at(c)
+	if l.InGamut() {
+		return l.NRGBA()
+	}
+	lo, hi := 0.0, c
+	for i := 0; i < 24; i++ {
+		mid := (lo + hi) / 2
+		if at(mid).InGamut() {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return at(lo).NRGBA()
+}
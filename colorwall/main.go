@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -15,7 +16,7 @@ var (
 	screenHeight int = 1000
 	bgcolor          = color.NRGBA{0xdd, 0xdd, 0xdd, 0xdd}
 
-	layout = [][]string{
+	wall = [][]string{
 		{"#000000", "#eeeeee", "#735976", "#eeeeee", "#000000", "#af5d23", "#eeeeee", "#366e93"}, // row 1
 		{"#eeeeee", "#03342f", "#000000", "#eeeeee", "#ccb04d", "#eeeeee", "#a74e4a", "#000000"}, // row 2
 		{"#000000", "#eeeeee", "#eeeeee", "#391a32", "#eeeeee", "#eeeeee", "#eeeeee", "#af5d23"}, // row 3
@@ -48,21 +49,13 @@ func colorwall(screen *ebiten.Image) {
 	canvas.Height = screenHeight
 	canvas.Width = screenWidth
 
-	var x, y, left, right, top, bottom, xincr, yincr float32
-	left, right, bottom, top = 25, 85, 20, 80
-	nr, nc := 8, 8
-
-	xincr = (right - left) / float32(nr)
-	yincr = (top - bottom) / float32(nc)
 	canvas.Background(bgcolor)
-	y = top
-	for i := range nr {
-		x = left
-		for j := range nc {
-			canvas.Square(x, y, yincr-0.1, ebcanvas.ColorLookup(layout[i][j]))
-			x += xincr
+	cells := layout.Rect{X: 20, Y: 20, W: 60, H: 60}.Grid(len(wall), len(wall[0]), 0, 0)
+	for i, row := range cells {
+		for j, cell := range row {
+			x, y := cell.Center()
+			canvas.Square(x, y, cell.H-0.1, ebcanvas.ColorLookup(wall[i][j]))
 		}
-		y -= yincr
 	}
 }
 
@@ -1,18 +1,38 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
-	"math/rand"
 	"os"
 
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/capture"
+	"github.com/ajstarks/ebcanvas/gen"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
-type App struct{}
+type App struct {
+	seed int64
+	rec  *capture.Recorder
+}
 
-func (g *App) Update() error {
+func (a *App) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyQ) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return ebiten.Termination
+	}
+	if a.rec != nil {
+		if a.rec.Done() {
+			return ebiten.Termination
+		}
+		a.rec.Update()
+	}
+	// space makes a new drawing
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		a.seed = gen.TimeSeed()
+	}
 	return nil
 }
 
@@ -21,48 +41,63 @@ func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
 
-func (g *App) Draw(screen *ebiten.Image) {
-	rgb(screen)
+func (a *App) Draw(screen *ebiten.Image) {
+	confetti(screen, a.seed)
+	if a.rec != nil {
+		a.rec.Capture(screen)
+	}
 }
 
 var screenWidth = 1000
 var screenHeight = 1000
 
-func rn(n int) float32 {
-	return float32(rand.Intn(n))
-}
-
-func rn8(n int) uint8 {
-	return uint8(rand.Intn(n))
-}
-
-func rgb(screen *ebiten.Image) {
+// confetti draws random circles and squares; the same seed makes the same drawing
+func confetti(screen *ebiten.Image, seed int64) {
 	canvas := new(ebcanvas.Canvas)
 	canvas.Screen = screen
 	canvas.Width = screenWidth
 	canvas.Height = screenHeight
 
+	r := gen.New(seed)
 	nshapes := 100
-	maxsize := 10
+	var maxsize float32 = 10
 	canvas.Screen.Fill(color.NRGBA{0, 0, 0, 255})
 	for i := 0; i < nshapes; i++ {
-		color := color.NRGBA{rn8(255), rn8(255), rn8(255), rn8(255)}
-		x, y := rn(100), rn(100)
-		w, h := rn(maxsize), rn(maxsize)
+		color := color.NRGBA{uint8(r.Intn(255)), uint8(r.Intn(255)), uint8(r.Intn(255)), uint8(r.Intn(255))}
+		x, y := r.Float(0, 100), r.Float(0, 100)
+		w, h := r.Float(0, maxsize), r.Float(0, maxsize)
 		if i%2 == 0 {
 			canvas.Circle(x, y, w, color)
 		} else {
 			canvas.CenterRect(x, y, w, h, color)
 		}
 	}
+	canvas.Text(2, 2, 1.5, fmt.Sprintf("seed %d", seed), color.NRGBA{200, 200, 200, 255})
 }
 
 func main() {
+	var pngfile string
+	a := &App{}
+	flag.Int64Var(&a.seed, "seed", gen.TimeSeed(), "random seed")
+	flag.StringVar(&pngfile, "png", "", "write PNG to the named file, and exit")
+	flag.Parse()
+
+	if len(pngfile) > 0 {
+		a.rec = capture.NewRecorder(pngfile, 1)
+		a.rec.Start()
+	}
+	if err := ebcanvas.LoadFont(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowTitle("confetti")
-	ebiten.SetTPS(0)
-	if err := ebiten.RunGame(&App{}); err != nil {
+	if err := ebiten.RunGame(a); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
-		return
+		os.Exit(2)
+	}
+	if a.rec != nil && a.rec.Err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", a.rec.Err)
+		os.Exit(3)
 	}
 }
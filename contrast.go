@@ -0,0 +1,229 @@
+package ebcanvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Accessibility: contrast and color vision deficiency
+
+// WCAG 2 contrast ratios
+const (
+	ContrastAALarge = 3.0 // AA for large text (18pt, or 14pt bold) and graphics
+	ContrastAA      = 4.5 // AA for text
+	ContrastAAA     = 7.0 // AAA for text
+)
+
+// Luminance returns the WCAG relative luminance of a color (0-1), ignoring alpha
+func Luminance(c color.NRGBA) float64 {
+	l := ToLinear(c)
+	return 0.2126*l.R + 0.7152*l.G + 0.0722*l.B
+}
+
+// Contrast returns the WCAG contrast ratio between two colors, from 1 (none) to 21 (black and white)
+func Contrast(a, b color.NRGBA) float64 {
+	la, lb := Luminance(a), Luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ReadableColor returns the color nearest fg, in lightness, that has at least the contrast ratio
+// against bg (for example ContrastAA), keeping the hue of fg.
+// If no lightness is enough, it returns black or white, whichever contrasts more.
+func ReadableColor(fg, bg color.NRGBA, ratio float64) color.NRGBA {
+	if Contrast(fg, bg) >= ratio {
+		return fg
+	}
+	lch := ToOKLCH(fg)
+	at := func(l float64) color.NRGBA {
+		c := OKLCH{l, lch.C, lch.H}.NRGBA()
+		c.A = fg.A
+		return c
+	}
+	// the lightness nearest fg in each direction with enough contrast;
+	// contrast grows as the lightness moves away from that of the background
+	bgl := ToOKLab(bg).L
+	search := func(l, end float64) (float64, bool) {
+		if Contrast(at(end), bg) < ratio {
+			return 0, false
+		}
+		for i := 0; i < 24; i++ {
+			mid := (l + end) / 2
+			if Contrast(at(mid), bg) >= ratio {
+				end = mid
+			} else {
+				l = mid
+			}
+		}
+		return end, true
+	}
+	dark, dok := search(math.Min(lch.L, bgl), 0)
+	light, lok := search(math.Max(lch.L, bgl), 1)
+	switch {
+	case dok && (!lok || lch.L-dark <= light-lch.L):
+		return at(dark)
+	case lok:
+		return at(light)
+	}
+	black, white := color.NRGBA{0, 0, 0, fg.A}, color.NRGBA{255, 255, 255, fg.A}
+	if Contrast(black, bg) >= Contrast(white, bg) {
+		return black
+	}
+	return white
+}
+
+// CVD is a kind of color vision deficiency
+type CVD int
+
+const (
+	Protanopia   CVD = iota // no red cones
+	Deuteranopia            // no green cones
+	Tritanopia              // no blue cones
+)
+
+// cvdmatrix holds the simulation matrices for linear RGB, from Machado, Oliveira and Fernandes,
+// "A Physiologically-based Model for Simulation of Color Vision Deficiency" (2009), at full severity
+var cvdmatrix = [...][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// ParseCVD returns the deficiency named "protanopia", "deuteranopia" or "tritanopia",
+// or their first letter
+func ParseCVD(s string) (CVD, bool) {
+	switch strings.ToLower(s) {
+	case "protanopia", "p":
+		return Protanopia, true
+	case "deuteranopia", "d":
+		return Deuteranopia, true
+	case "tritanopia", "t":
+		return Tritanopia, true
+	}
+	return 0, false
+}
+
+// SimulateCVD returns the color as seen with the deficiency
+func SimulateCVD(c color.NRGBA, kind CVD) color.NRGBA {
+	if kind < 0 || int(kind) >= len(cvdmatrix) {
+		return c
+	}
+	l := ToLinear(c)
+	r, g, b := mul(&cvdmatrix[kind], l.R, l.G, l.B)
+	s := LinearRGB{r, g, b}.NRGBA()
+	s.A = c.A
+	return s
+}
+
+// cvdcache remembers simulated colors, since images usually have far fewer colors than pixels
+type cvdcache struct {
+	kind   CVD
+	colors map[color.NRGBA]color.NRGBA
+}
+
+func (cc *cvdcache) simulate(c color.NRGBA) color.NRGBA {
+	s, ok := cc.colors[c]
+	if !ok {
+		s = SimulateCVD(c, cc.kind)
+		if len(cc.colors) < 1<<16 {
+			cc.colors[c] = s
+		}
+	}
+	return s
+}
+
+// SimulateCVDImage returns a copy of the image as seen with the deficiency
+func SimulateCVDImage(img image.Image, kind CVD) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(b)
+	cc := &cvdcache{kind, map[color.NRGBA]color.NRGBA{}}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			dst.SetNRGBA(x, y, cc.simulate(c))
+		}
+	}
+	return dst
+}
+
+// cvdshadersrc simulates a deficiency on the GPU: Red, Green and Blue are the rows of the matrix,
+// applied in linear RGB as SimulateCVD does
+const cvdshadersrc = `//kage:unit pixels
+
+package main
+
+var Red vec3
+var Green vec3
+var Blue vec3
+
+func tolinear(c vec3) vec3 {
+	return mix(c/12.92, pow((c+0.055)/1.055, vec3(2.4)), step(0.04045, c))
+}
+
+func togamma(c vec3) vec3 {
+	return mix(c*12.92, 1.055*pow(c, vec3(1/2.4))-0.055, step(0.0031308, c))
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	c := imageSrc0At(srcPos)
+	if c.a == 0 {
+		return c
+	}
+	l := tolinear(c.rgb / c.a)
+	s := clamp(vec3(dot(Red, l), dot(Green, l), dot(Blue, l)), 0, 1)
+	return vec4(togamma(s)*c.a, c.a)
+}
+`
+
+// cvdshader is the compiled cvdshadersrc, made on first use
+var cvdshader *ebiten.Shader
+
+// SimulateCVD redraws what has been drawn on the canvas as seen with the deficiency;
+// call it after drawing. The simulation runs on the GPU, so it may be called every frame.
+func (c *Canvas) SimulateCVD(kind CVD) {
+	if kind < 0 || int(kind) >= len(cvdmatrix) {
+		return
+	}
+	if cvdshader == nil {
+		s, err := ebiten.NewShader([]byte(cvdshadersrc))
+		if err != nil {
+			panic(err) // the source is constant
+		}
+		cvdshader = s
+	}
+	b := c.Screen.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// a shader cannot read the image it draws on, so work from a copy
+	src := scratch(cvdImage, w, h).SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+	op := &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy}
+	op.GeoM.Translate(-float64(b.Min.X), -float64(b.Min.Y))
+	src.DrawImage(c.Screen, op)
+
+	m := &cvdmatrix[kind]
+	row := func(i int) []float32 {
+		return []float32{float32(m[i][0]), float32(m[i][1]), float32(m[i][2])}
+	}
+	sop := &ebiten.DrawRectShaderOptions{Blend: ebiten.BlendCopy}
+	sop.Images[0] = src
+	sop.Uniforms = map[string]any{"Red": row(0), "Green": row(1), "Blue": row(2)}
+	sop.GeoM.Translate(float64(b.Min.X), float64(b.Min.Y))
+	c.Screen.DrawRectShader(w, h, cvdshader, sop)
+}
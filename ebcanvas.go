@@ -23,6 +23,8 @@ const (
 type Canvas struct {
 	Width, Height int
 	Screen        *ebiten.Image
+	ox, oy        float32 // pixel location of the upper left corner, for sub-canvases
+	sub           bool
 }
 
 var CurrentFont *text.GoTextFaceSource
@@ -70,6 +72,12 @@ func dimen(xp, yp, w, h float32) (float32, float32) {
 	return pct(xp, w), pct(100-yp, h)
 }
 
+// dimen calculates dimensions based on percentages, relative to the canvas origin
+func (c *Canvas) dimen(xp, yp, w, h float32) (float32, float32) {
+	x, y := dimen(xp, yp, w, h)
+	return x + c.ox, y + c.oy
+}
+
 // Scale for the display
 func DisplayScale(w, h int) (int, int) {
 	scale := ebiten.Monitor().DeviceScaleFactor()
@@ -237,6 +245,23 @@ func polygon(screen *ebiten.Image, x, y []float32, fillcolor color.NRGBA) {
 	vector.FillPath(screen, &p, fillOp, drawOp)
 }
 
+// polyline strokes the open path through the points in x and y, with round joins and ends
+func polyline(screen *ebiten.Image, x, y []float32, sw float32, strokecolor color.NRGBA) {
+	l := len(x)
+	if l != len(y) || l < 2 {
+		return
+	}
+	var p vector.Path
+	p.MoveTo(x[0], y[0])
+	for i := 1; i < l; i++ {
+		p.LineTo(x[i], y[i])
+	}
+	op := &vector.StrokeOptions{Width: sw, LineCap: vector.LineCapRound, LineJoin: vector.LineJoinRound}
+	drawOp := &vector.DrawPathOptions{AntiAlias: true}
+	drawOp.ColorScale.ScaleWithColor(strokecolor)
+	vector.StrokePath(screen, &p, op, drawOp)
+}
+
 // quadcurve draws a filled quadradic bezier curve beginning at (x1,y1),
 // with control point at (x2,y2), ending at (x3,y3)
 func quadcurve(screen *ebiten.Image, x1, y1, x2, y2, x3, y3 float32, fillcolor color.NRGBA) {
@@ -311,7 +336,7 @@ func (c *Canvas) CenterImage(x, y float32, scale float32, img image.Image) {
 	imw, imh := img.Bounds().Dx(), img.Bounds().Dy()                   // image dimensions
 	fimw, fimh := float32(imw)*scale*mscale, float32(imh)*scale*mscale // scaled image dimensions
 	cw, ch := float32(c.Width), float32(c.Height)
-	x, y = dimen(x, y, cw, ch)
+	x, y = c.dimen(x, y, cw, ch)
 	showimage(c.Screen, x-(fimw/2), y-(fimh/2), float64(scale), img)
 }
 
@@ -319,7 +344,7 @@ func (c *Canvas) CenterImage(x, y float32, scale float32, img image.Image) {
 // using percent-based coordinates and measures
 func (c *Canvas) CornerImage(x, y float32, scale float64, img image.Image) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x, y = dimen(x, y, cw, ch)
+	x, y = c.dimen(x, y, cw, ch)
 	showimage(c.Screen, x, y, scale/100, img)
 }
 
@@ -336,7 +361,7 @@ func (c *Canvas) Image(x, y float32, scale float32, img image.Image) {
 // between angles a1 and a2 (0-360 degrees, counter-clockwise)
 func (c *Canvas) Arc(cx, cy, r, a1, a2 float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy = dimen(cx, cy, cw, ch)
+	cx, cy = c.dimen(cx, cy, cw, ch)
 	r = pct(r, cw)
 	a1 = degreesToRadians(a1)
 	a2 = degreesToRadians(a2)
@@ -348,7 +373,7 @@ func (c *Canvas) Arc(cx, cy, r, a1, a2 float32, fillcolor color.NRGBA) {
 // between angles a1 and a2 (0-360 degrees, counter-clockwise)
 func (c *Canvas) StrokedArc(cx, cy, r, a1, a2, size float32, strokecolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy = dimen(cx, cy, cw, ch)
+	cx, cy = c.dimen(cx, cy, cw, ch)
 	r = pct(r, cw)
 	size = pct(size, cw)
 	a1 = degreesToRadians(a1)
@@ -375,7 +400,7 @@ func (c *Canvas) CenterRect(x, y, w, h float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
 	w = pct(w, cw)
 	h = pct(h, ch)
-	x, y = dimen(x, y, cw, ch)
+	x, y = c.dimen(x, y, cw, ch)
 	centerRect(c.Screen, x, y, w, h, fillcolor)
 }
 
@@ -385,7 +410,7 @@ func (c *Canvas) CornerRect(x, y, w, h float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
 	w = pct(w, cw)
 	h = pct(h, ch)
-	x, y = dimen(x, y, cw, ch)
+	x, y = c.dimen(x, y, cw, ch)
 	cornerRect(c.Screen, x, y, w, h, fillcolor)
 }
 
@@ -399,7 +424,7 @@ func (c *Canvas) Rect(x, y, w, h float32, fillcolor color.NRGBA) {
 // using percent-based coordinates and measures
 func (c *Canvas) Circle(cx, cy, r float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy = dimen(cx, cy, cw, ch)
+	cx, cy = c.dimen(cx, cy, cw, ch)
 	r = pct(r, cw)
 	circle(c.Screen, cx, cy, r, fillcolor)
 }
@@ -408,8 +433,8 @@ func (c *Canvas) Circle(cx, cy, r float32, fillcolor color.NRGBA) {
 // using percent-based coordinates and measures
 func (c *Canvas) Line(x1, y1, x2, y2, sw float32, strokecolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x1, y1 = dimen(x1, y1, cw, ch)
-	x2, y2 = dimen(x2, y2, cw, ch)
+	x1, y1 = c.dimen(x1, y1, cw, ch)
+	x2, y2 = c.dimen(x2, y2, cw, ch)
 	sw = pct(sw, cw)
 	line(c.Screen, x1, y1, x2, y2, sw, strokecolor)
 }
@@ -429,7 +454,7 @@ func (c *Canvas) VLine(x1, y1, size, sw float32, strokecolor color.NRGBA) {
 func (c *Canvas) Polygon(x, y []float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
 	for i := 0; i < len(x); i++ {
-		x[i], y[i] = dimen(x[i], y[i], cw, ch)
+		x[i], y[i] = c.dimen(x[i], y[i], cw, ch)
 	}
 	polygon(c.Screen, x, y, fillcolor)
 }
@@ -444,14 +469,29 @@ func (c *Canvas) StrokedPolygon(x, y []float32, size float32, strokecolor color.
 	c.Line(x[l], y[l], x[0], y[0], size, strokecolor)
 }
 
+// Polyline strokes the open path through the points in x and y, with round joins,
+// using percent-based coordinates and measures
+func (c *Canvas) Polyline(x, y []float32, size float32, strokecolor color.NRGBA) {
+	if len(x) != len(y) {
+		return
+	}
+	cw, ch := float32(c.Width), float32(c.Height)
+	px := make([]float32, len(x))
+	py := make([]float32, len(y))
+	for i := range x {
+		px[i], py[i] = c.dimen(x[i], y[i], cw, ch)
+	}
+	polyline(c.Screen, px, py, pct(size, cw), strokecolor)
+}
+
 // Quadcurve draws a filled quadradic bezier curve beginning at (x1,y1),
 // with control point at (x2,y2). ending at (x3,y3),
 // using percent-based coordinates and measures
 func (c *Canvas) QuadCurve(x1, y1, x2, y2, x3, y3 float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x1, y1 = dimen(x1, y1, cw, ch)
-	x2, y2 = dimen(x2, y2, cw, ch)
-	x3, y3 = dimen(x3, y3, cw, ch)
+	x1, y1 = c.dimen(x1, y1, cw, ch)
+	x2, y2 = c.dimen(x2, y2, cw, ch)
+	x3, y3 = c.dimen(x3, y3, cw, ch)
 	quadcurve(c.Screen, x1, y1, x2, y2, x3, y3, fillcolor)
 }
 
@@ -460,9 +500,9 @@ func (c *Canvas) QuadCurve(x1, y1, x2, y2, x3, y3 float32, fillcolor color.NRGBA
 // using percent-based coordinates and measures
 func (c *Canvas) QuadStrokedCurve(x1, y1, x2, y2, x3, y3, size float32, strokecolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x1, y1 = dimen(x1, y1, cw, ch)
-	x2, y2 = dimen(x2, y2, cw, ch)
-	x3, y3 = dimen(x3, y3, cw, ch)
+	x1, y1 = c.dimen(x1, y1, cw, ch)
+	x2, y2 = c.dimen(x2, y2, cw, ch)
+	x3, y3 = c.dimen(x3, y3, cw, ch)
 	size = pct(size, cw)
 	strokedquadcurve(c.Screen, x1, y1, x2, y2, x3, y3, size, strokecolor)
 }
@@ -472,10 +512,10 @@ func (c *Canvas) QuadStrokedCurve(x1, y1, x2, y2, x3, y3, size float32, strokeco
 // using percent-based coordinates and measures
 func (c *Canvas) CubeCurve(x1, y1, x2, y2, x3, y3, x4, y4 float32, strokecolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x1, y1 = dimen(x1, y1, cw, ch)
-	x2, y2 = dimen(x2, y2, cw, ch)
-	x3, y3 = dimen(x3, y3, cw, ch)
-	x4, y4 = dimen(x4, y4, cw, ch)
+	x1, y1 = c.dimen(x1, y1, cw, ch)
+	x2, y2 = c.dimen(x2, y2, cw, ch)
+	x3, y3 = c.dimen(x3, y3, cw, ch)
+	x4, y4 = c.dimen(x4, y4, cw, ch)
 	cubecurve(c.Screen, x1, y1, x2, y2, x3, y3, x4, y4, strokecolor)
 }
 
@@ -484,10 +524,10 @@ func (c *Canvas) CubeCurve(x1, y1, x2, y2, x3, y3, x4, y4 float32, strokecolor c
 // using percent-based coordinates and measures
 func (c *Canvas) StrokedCubeCurve(x1, y1, x2, y2, x3, y3, x4, y4, size float32, strokecolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x1, y1 = dimen(x1, y1, cw, ch)
-	x2, y2 = dimen(x2, y2, cw, ch)
-	x3, y3 = dimen(x3, y3, cw, ch)
-	x4, y4 = dimen(x4, y4, cw, ch)
+	x1, y1 = c.dimen(x1, y1, cw, ch)
+	x2, y2 = c.dimen(x2, y2, cw, ch)
+	x3, y3 = c.dimen(x3, y3, cw, ch)
+	x4, y4 = c.dimen(x4, y4, cw, ch)
 	size = pct(size, cw)
 	strokedcubecurve(c.Screen, x1, y1, x2, y2, x3, y3, x4, y4, size, strokecolor)
 }
@@ -505,7 +545,7 @@ func (c *Canvas) StrokedCurve(x1, y1, x2, y2, x3, y3, size float32, strokecolor
 // Square draws a filled square centered at (x,y), sides at size
 func (c *Canvas) Square(x, y, w float32, fillcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	x, y = dimen(x, y, cw, ch)
+	x, y = c.dimen(x, y, cw, ch)
 	w = pct(w, ch)
 	h := pct(100, w)
 	centerRect(c.Screen, x, y, w, h, fillcolor)
@@ -517,7 +557,7 @@ func (c *Canvas) Square(x, y, w float32, fillcolor color.NRGBA) {
 // using percent-based coordinates and measures
 func (c *Canvas) Text(x, y, size float32, s string, textcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy := dimen(x, y, cw, ch)
+	cx, cy := c.dimen(x, y, cw, ch)
 	size = pct(size, cw)
 	btext(c.Screen, float64(cx), float64(cy), float64(size), s, textcolor)
 }
@@ -526,7 +566,7 @@ func (c *Canvas) Text(x, y, size float32, s string, textcolor color.NRGBA) {
 // using percent-based coordinates and measures
 func (c *Canvas) CText(x, y, size float32, s string, textcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy := dimen(x, y, cw, ch)
+	cx, cy := c.dimen(x, y, cw, ch)
 	size = pct(size, cw)
 	ctext(c.Screen, float64(cx), float64(cy), float64(size), s, textcolor)
 }
@@ -539,7 +579,7 @@ func (c *Canvas) TextMid(x, y, size float32, s string, textcolor color.NRGBA) {
 // RText draws rotated text at (x,y), rotated at the specified angle
 func (c *Canvas) RText(x, y, angle, size float32, s string, textcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy := dimen(x, y, cw, ch)
+	cx, cy := c.dimen(x, y, cw, ch)
 	size = pct(size, cw)
 	theta := degreesToRadians(angle)
 	rtext(c.Screen, float64(cx), float64(cy), float64(theta), float64(size), s, textcolor)
@@ -549,7 +589,7 @@ func (c *Canvas) RText(x, y, angle, size float32, s string, textcolor color.NRGB
 // using percent-based coordinates and measures
 func (c *Canvas) EText(x, y, size float32, s string, textcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy := dimen(x, y, cw, ch)
+	cx, cy := c.dimen(x, y, cw, ch)
 	size = pct(size, cw)
 	etext(c.Screen, float64(cx), float64(cy), float64(size), s, textcolor)
 }
@@ -562,7 +602,7 @@ func (c *Canvas) TextEnd(x, y, size float32, s string, textcolor color.NRGBA) {
 // TextWrap wraps text starting at (x,y), to x+w, overflow is permitted
 func (c *Canvas) TextWrap(x, y, w, size float32, s string, textcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy := dimen(x, y, cw, ch)
+	cx, cy := c.dimen(x, y, cw, ch)
 	size = pct(size, cw)
 	w = pct(w, cw)
 	ls := float64(size * lsf)
@@ -572,20 +612,68 @@ func (c *Canvas) TextWrap(x, y, w, size float32, s string, textcolor color.NRGBA
 // TextWrap wraps text starting at (x,y), to x+w, never overflowing the edge
 func (c *Canvas) TextWrapStrict(x, y, w, size float32, s string, textcolor color.NRGBA) {
 	cw, ch := float32(c.Width), float32(c.Height)
-	cx, cy := dimen(x, y, cw, ch)
+	cx, cy := c.dimen(x, y, cw, ch)
 	size = pct(size, cw)
 	w = pct(w, cw)
 	ls := float64(size * lsf)
 	textwraps(c.Screen, float64(cx), float64(cy), float64(w), ls, float64(size), s, textcolor)
 }
 
+// TextWidth returns the width of s at the specified size,
+// using percent-based measures
+func (c *Canvas) TextWidth(s string, size float32) float32 {
+	cw := float32(c.Width)
+	ff := &text.GoTextFace{Source: CurrentFont, Size: float64(pct(size, cw))}
+	return float32(text.Advance(s, ff)) / cw * 100
+}
+
 // Utility Methods
 
 // Background fills the canvas with the specified color
 func (c *Canvas) Background(fillcolor color.NRGBA) {
+	if c.sub {
+		cornerRect(c.Screen, c.ox, c.oy, float32(c.Width), float32(c.Height), fillcolor)
+		return
+	}
 	c.Screen.Fill(fillcolor)
 }
 
+// Sub-canvas methods: a sub-canvas maps its own 0-100 coordinate system,
+// and measures, onto a region of its parent.
+
+// Sub returns a canvas covering the region with lower left corner at (x,y)
+// and dimensions (w,h), using percent-based coordinates and measures.
+// Drawing is not clipped to the region.
+func (c *Canvas) Sub(x, y, w, h float32) *Canvas {
+	cw, ch := float32(c.Width), float32(c.Height)
+	px, py := c.dimen(x, y+h, cw, ch)
+	return &Canvas{
+		Width:  int(math.Round(float64(pct(w, cw)))),
+		Height: int(math.Round(float64(pct(h, ch)))),
+		Screen: c.Screen,
+		ox:     px,
+		oy:     py,
+		sub:    true,
+	}
+}
+
+// ClipSub returns a sub-canvas like Sub, with drawing clipped to the region
+func (c *Canvas) ClipSub(x, y, w, h float32) *Canvas {
+	s := c.Sub(x, y, w, h)
+	r := image.Rect(int(s.ox), int(s.oy), int(s.ox)+s.Width, int(s.oy)+s.Height)
+	if clip, ok := c.Screen.SubImage(r).(*ebiten.Image); ok {
+		s.Screen = clip
+	}
+	return s
+}
+
+// Inset returns a sub-canvas with padding p on every side,
+// using percent-based measures (p is a percent of the width on the left and right,
+// and a percent of the height on the top and bottom)
+func (c *Canvas) Inset(p float32) *Canvas {
+	return c.Sub(p, p, 100-(2*p), 100-(2*p))
+}
+
 // Grid draws a grid starting at (x,y), dimensions at (w,h),
 // A gridline is drawn at the specifed interval.
 func (c *Canvas) Grid(x, y, w, h, size, interval float32, strokecolor color.NRGBA) {
@@ -636,6 +724,14 @@ func (c *Canvas) Coord(x, y, size float32, s string, fillcolor color.NRGBA) {
 	}
 }
 
+// PercentPoint converts the pixel location (px, py), for example the cursor position,
+// to percent-based coordinates
+func (c *Canvas) PercentPoint(px, py int) (float32, float32) {
+	cw, ch := float32(c.Width), float32(c.Height)
+	x, y := float32(px)-c.ox, float32(py)-c.oy
+	return (x / cw) * 100, 100 - ((y / ch) * 100)
+}
+
 // MapRange maps a value between low1 and high1, return the corresponding value between low2 and high2
 func MapRange(value, low1, high1, low2, high2 float64) float64 {
 	return low2 + (high2-low2)*(value-low1)/(high1-low1)
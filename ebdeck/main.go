@@ -16,6 +16,7 @@ import (
 
 	"github.com/ajstarks/deck"
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/internal/filetime"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -26,6 +27,7 @@ type App struct {
 	nslides     int
 	deckname    string
 	d           deck.Deck
+	warnings    string // the warnings about the deck last shown
 }
 
 // command line options
@@ -38,6 +40,9 @@ type options struct {
 	pages         string
 	pagesize      string
 	fontdir       string
+	textfx        string
+	cvd           string
+	theme         string
 	gridpct       float64
 	width, height int
 }
@@ -49,11 +54,10 @@ type PageDimen struct {
 }
 
 const (
-	mm2pt        = 2.83464 // mm to pt conversion
-	linespacing  = 1.8
-	listspacing  = 1.5
-	listwrap     = 95.0
-	defaultColor = "rgb(128,128,128)"
+	mm2pt       = 2.83464 // mm to pt conversion
+	linespacing = 1.8
+	listspacing = 1.5
+	listwrap    = 95.0
 )
 
 var (
@@ -61,6 +65,7 @@ var (
 	gridstate                 bool
 	codemap                   = strings.NewReplacer("\t", "    ") // convert tyabs to spaces
 	opts                      options                             // command line options
+	theme                     ebcanvas.Theme                      // colors and fonts not given by the deck
 	screenWidth, screenHeight int                                 // screen width, height
 
 	imagecache = map[string]image.Image{}
@@ -97,6 +102,7 @@ func (a *App) Draw(screen *ebiten.Image) {
 	// if the deckfile has changed, reload
 	t, err := modtime(a.deckname)
 	if len(a.deckname) > 0 && err == nil && t.After(btime) {
+		btime = t
 		a.dodeck()
 	}
 	ebdeck(a, screen)
@@ -153,12 +159,12 @@ func process(a *App, canvas *ebcanvas.Canvas) {
 
 	var bg color.NRGBA
 	if slide.Bg == "" {
-		bg = color.NRGBA{255, 255, 255, 255}
+		bg = theme.Background
 	} else {
 		bg = ebcanvas.ColorLookup(slide.Bg)
 	}
 	if slide.Fg == "" {
-		slide.Fg = "black"
+		slide.Fg = ebcanvas.FormatRGB(theme.Foreground)
 	}
 	canvas.Background(bg)
 
@@ -221,8 +227,7 @@ func process(a *App, canvas *ebcanvas.Canvas) {
 
 // list processes lists
 func list(canvas *ebcanvas.Canvas, list deck.List) {
-	c := ebcanvas.ColorLookup(list.Color)
-	c.A = setopacity(list.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(list.Color), list.Opacity)
 	var xp, yp, ls, ts float32
 	xp = float32(list.Xp)
 	yp = float32(list.Yp)
@@ -262,20 +267,18 @@ func list(canvas *ebcanvas.Canvas, list deck.List) {
 // arc makes arcs
 func arc(canvas *ebcanvas.Canvas, a deck.Arc) {
 	if a.Color == "" {
-		a.Color = defaultColor
+		a.Color = ebcanvas.FormatRGB(theme.Muted)
 	}
-	c := ebcanvas.ColorLookup(a.Color)
-	c.A = setopacity(a.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(a.Color), a.Opacity)
 	canvas.Arc(float32(a.Xp), float32(a.Yp), float32(a.Wp/2), float32(a.A1), float32(a.A2), c)
 }
 
 // curve makea a quad bezier curve
 func curve(canvas *ebcanvas.Canvas, curve deck.Curve) {
 	if curve.Color == "" {
-		curve.Color = defaultColor
+		curve.Color = ebcanvas.FormatRGB(theme.Muted)
 	}
-	c := ebcanvas.ColorLookup(curve.Color)
-	c.A = setopacity(curve.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(curve.Color), curve.Opacity)
 	x1, y1 := float32(curve.Xp1), float32(curve.Yp1)
 	x2, y2 := float32(curve.Xp2), float32(curve.Yp2)
 	x3, y3 := float32(curve.Xp3), float32(curve.Yp3)
@@ -286,10 +289,9 @@ func curve(canvas *ebcanvas.Canvas, curve deck.Curve) {
 // rect makes rectangles and squares
 func rect(canvas *ebcanvas.Canvas, r deck.Rect) {
 	if r.Color == "" {
-		r.Color = defaultColor
+		r.Color = ebcanvas.FormatRGB(theme.Muted)
 	}
-	c := ebcanvas.ColorLookup(r.Color)
-	c.A = setopacity(r.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(r.Color), r.Opacity)
 	x, y, w, h := float32(r.Xp), float32(r.Yp), float32(r.Wp), float32(r.Hp)
 	if r.Hr == 100 {
 		canvas.Square(x, y, w, c)
@@ -325,10 +327,9 @@ func poly(canvas *ebcanvas.Canvas, p deck.Polygon) {
 		}
 	}
 	if p.Color == "" {
-		p.Color = defaultColor
+		p.Color = ebcanvas.FormatRGB(theme.Muted)
 	}
-	c := ebcanvas.ColorLookup(p.Color)
-	c.A = setopacity(p.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(p.Color), p.Opacity)
 	canvas.Polygon(xp, yp, c)
 }
 
@@ -338,31 +339,56 @@ func ellipse(canvas *ebcanvas.Canvas, e deck.Ellipse) {
 		return
 	}
 	if e.Color == "" {
-		e.Color = defaultColor
+		e.Color = ebcanvas.FormatRGB(theme.Muted)
 	}
-	c := ebcanvas.ColorLookup(e.Color)
-	c.A = setopacity(e.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(e.Color), e.Opacity)
 	canvas.Circle(float32(e.Xp), float32(e.Yp), float32(e.Wp/2), c)
 }
 
 // line makes lines
 func line(canvas *ebcanvas.Canvas, l deck.Line) {
 	if l.Color == "" {
-		l.Color = defaultColor
+		l.Color = ebcanvas.FormatRGB(theme.Muted)
 	}
-	c := ebcanvas.ColorLookup(l.Color)
-	c.A = setopacity(l.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(l.Color), l.Opacity)
 	canvas.Line(float32(l.Xp1), float32(l.Yp1), float32(l.Xp2), float32(l.Yp2), float32(l.Sp), c)
 }
 
-// dtext processes text
+// texteffect returns the effect for text of the specified color,
+// in a contrasting color, so the text stands out from the background
+func texteffect(name string, textcolor color.NRGBA) (ebcanvas.Effect, bool) {
+	fx := color.NRGBA{0, 0, 0, 200}
+	if (299*int(textcolor.R)+587*int(textcolor.G)+114*int(textcolor.B))/1000 < 128 {
+		fx = color.NRGBA{255, 255, 255, 200}
+	}
+	switch name {
+	case "shadow":
+		return ebcanvas.DropShadow(0.2, -0.2, 0.4, fx), true
+	case "outline":
+		return ebcanvas.Outline(0.15, fx), true
+	case "glow":
+		return ebcanvas.Glow(1, fx), true
+	}
+	return ebcanvas.Effect{}, false
+}
+
+// dtext processes text, with the text effect, if any
 func dtext(canvas *ebcanvas.Canvas, t deck.Text) {
+	e, ok := texteffect(opts.textfx, ebcanvas.ColorLookup(t.Color))
+	if !ok || t.Type == "code" {
+		drawtext(canvas, t)
+		return
+	}
+	canvas.WithEffect(e, func(canvas *ebcanvas.Canvas) { drawtext(canvas, t) })
+}
+
+// drawtext draws text
+func drawtext(canvas *ebcanvas.Canvas, t deck.Text) {
 	if t.Font == "" {
 		t.Font = "sans"
 	}
 	x, y, ts := float32(t.Xp), float32(t.Yp), float32(t.Sp)
-	c := ebcanvas.ColorLookup(t.Color)
-	c.A = setopacity(t.Opacity)
+	c := setopacity(ebcanvas.ColorLookup(t.Color), t.Opacity)
 	ebcanvas.CurrentFont = fontmap[t.Font]
 
 	s := t.Tdata
@@ -434,17 +460,11 @@ func dimage(canvas *ebcanvas.Canvas, img image.Image, i deck.Image) {
 // 0 == default value (opaque)
 // -1 == fully transparent
 // > 0 set opacity percent
-func setopacity(v float64) uint8 {
-	var o uint8
-	switch {
-	case v < 0:
-		o = 0
-	case v > 0:
-		o = uint8(255.0 * (v / 100))
-	case v == 0:
-		o = 255
+func setopacity(c color.NRGBA, v float64) color.NRGBA {
+	if v == 0 {
+		return ebcanvas.WithAlpha(c, 100)
 	}
-	return o
+	return ebcanvas.WithAlpha(c, v)
 }
 
 // bullet draws a bullet for a list item.
@@ -491,6 +511,9 @@ func ebdeck(a *App, screen *ebiten.Image) {
 		a.slideNumber = a.nslides
 	}
 	process(a, canvas)
+	if kind, ok := ebcanvas.ParseCVD(opts.cvd); ok {
+		canvas.SimulateCVD(kind)
+	}
 }
 
 // imageinfo returns an image from a named file
@@ -564,8 +587,11 @@ func setfontdir(s string) string {
 	return path.Join(os.Getenv("HOME"), "deckfonts")
 }
 
-// loadDeckFont gets fonts from the font directory
+// loadDeckFont gets fonts from the font directory; without a name, the font is the theme's
 func loadDeckFont(dname, name string) {
+	if name == "" {
+		name = theme.Font(dname)
+	}
 	f, err := ebcanvas.LoadFontName(path.Join(opts.fontdir, name) + ".ttf")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -574,13 +600,12 @@ func loadDeckFont(dname, name string) {
 	fontmap[dname] = f
 }
 
-// modtime returns the modification time of a file
+// modtime returns the modification time of a file; standard input ("") is always new
 func modtime(filename string) (time.Time, error) {
 	if filename == "" {
 		return time.Now(), nil
 	}
-	s, err := os.Stat(filename)
-	return s.ModTime(), err
+	return filetime.Mod(filename)
 }
 
 func (a *App) updateDeck() (io.ReadCloser, error) {
@@ -594,6 +619,99 @@ func (a *App) updateDeck() (io.ReadCloser, error) {
 	return r, err
 }
 
+// checkcolors writes warnings about colors in the deck that are not understood,
+// and text that is hard to read, once for each slide
+func checkcolors(w io.Writer, d deck.Deck, name string) {
+	if name == "" {
+		name = "stdin"
+	}
+	for i, slide := range d.Slide {
+		colors := []string{slide.Bg, slide.Fg, slide.Gradcolor1, slide.Gradcolor2}
+		for _, l := range slide.List {
+			colors = append(colors, l.Color, l.Gradcolor1, l.Gradcolor2)
+			for _, li := range l.Li {
+				colors = append(colors, li.Color)
+			}
+		}
+		for _, t := range slide.Text {
+			colors = append(colors, t.Color)
+		}
+		for _, r := range slide.Rect {
+			colors = append(colors, r.Color)
+		}
+		for _, e := range slide.Ellipse {
+			colors = append(colors, e.Color)
+		}
+		for _, a := range slide.Arc {
+			colors = append(colors, a.Color)
+		}
+		for _, l := range slide.Line {
+			colors = append(colors, l.Color)
+		}
+		for _, c := range slide.Curve {
+			colors = append(colors, c.Color)
+		}
+		for _, p := range slide.Polygon {
+			colors = append(colors, p.Color)
+		}
+		for _, p := range slide.Polyline {
+			colors = append(colors, p.Color)
+		}
+		seen := map[string]bool{}
+		for _, c := range colors {
+			if c == "" || seen[c] {
+				continue
+			}
+			seen[c] = true
+			if _, err := ebcanvas.ParseColor(c); err != nil {
+				fmt.Fprintf(w, "%s: slide %d: %v\n", name, i+1, err)
+			}
+		}
+		checkcontrast(w, slide, fmt.Sprintf("%s: slide %d", name, i+1))
+	}
+}
+
+// checkcontrast warns about text colors that are hard to read against the slide background
+// (a contrast ratio below WCAG AA); slides with gradient backgrounds are not checked
+func checkcontrast(w io.Writer, slide deck.Slide, where string) {
+	if slide.Gradcolor1 != "" || slide.Gradcolor2 != "" {
+		return
+	}
+	bgname, fg := slide.Bg, slide.Fg
+	if bgname == "" {
+		bgname = ebcanvas.FormatCSS(theme.Background)
+	}
+	if fg == "" {
+		fg = ebcanvas.FormatCSS(theme.Foreground)
+	}
+	bg, err := ebcanvas.ParseColor(bgname)
+	if err != nil {
+		return
+	}
+	colors := []string{fg}
+	for _, t := range slide.Text {
+		if t.Color != "" {
+			colors = append(colors, t.Color)
+		}
+	}
+	for _, l := range slide.List {
+		if l.Color != "" {
+			colors = append(colors, l.Color)
+		}
+	}
+	seen := map[string]bool{}
+	for _, name := range colors {
+		c, err := ebcanvas.ParseColor(name)
+		if seen[name] || err != nil {
+			continue
+		}
+		seen[name] = true
+		if r := ebcanvas.Contrast(c, bg); r < ebcanvas.ContrastAA {
+			fmt.Fprintf(w, "%s: text color %q on %q has contrast %.1f, below %.1f\n", where, name, bgname, r, ebcanvas.ContrastAA)
+		}
+	}
+}
+
 // dodeck reads a deck, caching all images,
 func (a *App) dodeck() {
 	r, err := a.updateDeck()
@@ -606,6 +724,13 @@ func (a *App) dodeck() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return
 	}
+	// show warnings when they change, not each time the deck is saved
+	var warnings strings.Builder
+	checkcolors(&warnings, d, a.deckname)
+	if w := warnings.String(); w != a.warnings {
+		fmt.Fprint(os.Stderr, w)
+		a.warnings = w
+	}
 	// cache all images
 	ns := len(d.Slide)
 	a.nslides = ns
@@ -631,17 +756,27 @@ func (a *App) dodeck() {
 
 func main() {
 	// parse command line options
-	flag.StringVar(&opts.sansfont, "sans", "PublicSans-Regular", "sans font")
-	flag.StringVar(&opts.monofont, "mono", "Inconsolata-Medium", "mono font")
-	flag.StringVar(&opts.serifont, "serif", "Charter-Regular", "sans font")
-	flag.StringVar(&opts.symbolfont, "symbol", "ZapfDingbats", "sans font")
+	flag.StringVar(&opts.sansfont, "sans", "", "sans font (\"\": the theme's)")
+	flag.StringVar(&opts.monofont, "mono", "", "mono font (\"\": the theme's)")
+	flag.StringVar(&opts.serifont, "serif", "", "serif font (\"\": the theme's)")
+	flag.StringVar(&opts.symbolfont, "symbol", "", "symbol font (\"\": the theme's)")
+	flag.StringVar(&opts.theme, "theme", "light", "theme: light, dark, or a theme file")
 	flag.StringVar(&opts.layers, "layers", "image:rect:ellipse:curve:arc:line:poly:text:list", "Layer order")
 	flag.StringVar(&opts.pagesize, "pagesize", "Letter", "pagesize: w,h, or one of: Letter, Legal, Tabloid, A3, A4, A5, ArchA, 4R, Index, Widescreen")
 	flag.StringVar(&opts.pages, "pages", "1-1000000", "page range (first-last)")
 	flag.StringVar(&opts.fontdir, "fontdir", setfontdir(""), "directory for fonts")
 	flag.Float64Var(&opts.gridpct, "grid", 0, "grid size (0 for no grid)")
+	flag.StringVar(&opts.textfx, "textfx", "", "text effect: shadow, outline, glow (\"\": none)")
+	flag.StringVar(&opts.cvd, "cvd", "", "simulate color vision deficiency: protanopia, deuteranopia, tritanopia (\"\": none)")
 	flag.Parse()
+	if _, ok := ebcanvas.ParseCVD(opts.cvd); opts.cvd != "" && !ok {
+		fmt.Fprintf(os.Stderr, "-cvd: unknown deficiency %q\n", opts.cvd)
+	}
 
+	var err error
+	if theme, err = ebcanvas.LoadTheme(opts.theme); err != nil {
+		fmt.Fprintf(os.Stderr, "-theme: %v\n", err)
+	}
 	loadDeckFont("sans", opts.sansfont)
 	loadDeckFont("serif", opts.serifont)
 	loadDeckFont("mono", opts.monofont)
@@ -667,7 +802,6 @@ func main() {
 		a.deckname = files[0]
 
 	}
-	var err error
 	btime, err = modtime(a.deckname)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
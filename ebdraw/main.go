@@ -0,0 +1,140 @@
+// ebdraw: run a drawing script, redrawing when it changes
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/capture"
+	"github.com/ajstarks/ebcanvas/internal/filetime"
+	"github.com/ajstarks/ebcanvas/script"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type App struct {
+	filename string
+	prog     *script.Program
+	modtime  time.Time
+	frame    int
+	lasterr  string
+	rec      *capture.Recorder
+}
+
+var screenWidth, screenHeight int
+
+func (a *App) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyQ) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return ebiten.Termination
+	}
+	if a.rec != nil {
+		if a.rec.Done() {
+			return ebiten.Termination
+		}
+		a.rec.Update()
+	}
+	// if the script, or a file it includes, has changed, reload
+	if t, err := a.lastmod(); err == nil && t.After(a.modtime) {
+		a.load()
+	}
+	a.frame++
+	return nil
+}
+
+func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
+	screenWidth, screenHeight = ebcanvas.DisplayScale(outsideWidth, outsideHeight)
+	return screenWidth, screenHeight
+}
+
+func (a *App) Draw(screen *ebiten.Image) {
+	canvas := new(ebcanvas.Canvas)
+	canvas.Screen = screen
+	canvas.Width = screenWidth
+	canvas.Height = screenHeight
+	if a.prog != nil {
+		mx, my := ebiten.CursorPosition()
+		x, y := canvas.PercentPoint(mx, my)
+		a.prog.Set("frame", float64(a.frame))
+		a.prog.Set("mousex", float64(x))
+		a.prog.Set("mousey", float64(y))
+		// report each error once, not every frame
+		if err := a.prog.Run(canvas); err != nil && err.Error() != a.lasterr {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			a.lasterr = err.Error()
+		}
+	}
+	if a.rec != nil {
+		a.rec.Capture(screen)
+	}
+}
+
+// load parses the script, keeping the current program on error
+func (a *App) load() {
+	t, err := filetime.Mod(a.filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	a.modtime = t
+	p, err := script.ParseFile(a.filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	a.prog = p
+	a.lasterr = ""
+	if t, err := a.lastmod(); err == nil {
+		a.modtime = t
+	}
+}
+
+// lastmod returns the latest modification time of the script and its includes
+func (a *App) lastmod() (time.Time, error) {
+	if a.prog == nil {
+		return filetime.Latest(a.filename)
+	}
+	return filetime.Latest(a.prog.Files()...)
+}
+
+func main() {
+	var width, height int
+	var pngfile string
+	flag.IntVar(&width, "width", 1000, "canvas width")
+	flag.IntVar(&height, "height", 1000, "canvas height")
+	flag.StringVar(&pngfile, "png", "", "write PNG to the named file, and exit")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: ebdraw [options] file.dsh")
+		os.Exit(1)
+	}
+	a := &App{filename: flag.Arg(0)}
+	a.load()
+	if a.prog == nil {
+		os.Exit(1)
+	}
+	if len(pngfile) > 0 {
+		a.rec = capture.NewRecorder(pngfile, 1)
+		a.rec.Start()
+	}
+	if err := ebcanvas.LoadFont(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	screenWidth, screenHeight = width, height
+	ebiten.SetWindowSize(width, height)
+	ebiten.SetWindowTitle(a.filename)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	if err := ebiten.RunGame(a); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(3)
+	}
+	if a.rec != nil && a.rec.Err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", a.rec.Err)
+		os.Exit(4)
+	}
+}
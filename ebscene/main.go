@@ -0,0 +1,153 @@
+// ebscene: render a JSON scene file to a window, PNG or SVG
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/capture"
+	"github.com/ajstarks/ebcanvas/internal/filetime"
+	"github.com/ajstarks/ebcanvas/scenefile"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type App struct {
+	filename string
+	scene    *scenefile.Scene
+	modtime  time.Time
+	rec      *capture.Recorder
+}
+
+var screenWidth, screenHeight int
+
+func (a *App) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyQ) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return ebiten.Termination
+	}
+	if a.rec != nil {
+		if a.rec.Done() {
+			return ebiten.Termination
+		}
+		a.rec.Update()
+	}
+	// if the scene file has changed, reload
+	if t, err := filetime.Mod(a.filename); err == nil && t.After(a.modtime) {
+		a.load()
+	}
+	return nil
+}
+
+func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
+	screenWidth, screenHeight = ebcanvas.DisplayScale(outsideWidth, outsideHeight)
+	return screenWidth, screenHeight
+}
+
+func (a *App) Draw(screen *ebiten.Image) {
+	canvas := new(ebcanvas.Canvas)
+	canvas.Screen = screen
+	canvas.Width = screenWidth
+	canvas.Height = screenHeight
+	if a.scene != nil {
+		if err := a.scene.Draw(canvas); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			a.scene = nil
+		}
+	}
+	if a.rec != nil {
+		a.rec.Capture(screen)
+	}
+}
+
+// load reads the scene file, keeping the current scene on error
+func (a *App) load() {
+	t, err := filetime.Mod(a.filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	a.modtime = t
+	s, err := scenefile.ReadFile(a.filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	a.scene = s
+}
+
+// writesvg renders the scene as SVG
+func writesvg(s *scenefile.Scene, name string, width, height int) error {
+	w, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := s.WriteSVG(w, width, height); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func main() {
+	var width, height int
+	var svgfile, pngfile string
+	flag.IntVar(&width, "width", 0, "canvas width (default: scene width, or 1000)")
+	flag.IntVar(&height, "height", 0, "canvas height (default: scene height, or 1000)")
+	flag.StringVar(&svgfile, "svg", "", "write SVG to the named file, and exit")
+	flag.StringVar(&pngfile, "png", "", "write PNG to the named file, and exit")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: ebscene [options] scene.json")
+		os.Exit(1)
+	}
+	a := &App{filename: flag.Arg(0)}
+	a.load()
+	if a.scene == nil {
+		os.Exit(1)
+	}
+	if width == 0 {
+		width = a.scene.Width
+	}
+	if height == 0 {
+		height = a.scene.Height
+	}
+	if width == 0 {
+		width = 1000
+	}
+	if height == 0 {
+		height = 1000
+	}
+
+	if len(svgfile) > 0 {
+		if err := writesvg(a.scene, svgfile, width, height); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+		return
+	}
+	if len(pngfile) > 0 {
+		a.rec = capture.NewRecorder(pngfile, 1)
+		a.rec.Start()
+	}
+	if err := ebcanvas.LoadFont(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	screenWidth, screenHeight = width, height
+	ebiten.SetWindowSize(width, height)
+	ebiten.SetWindowTitle(a.filename)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	if err := ebiten.RunGame(a); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(3)
+	}
+	if a.rec != nil && a.rec.Err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", a.rec.Err)
+		os.Exit(4)
+	}
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"image/color"
 	"io"
 	"os"
 	"strconv"
@@ -18,6 +19,7 @@ import (
 var opts chartOptions
 var screenWidth, screenHeight int
 var data chart.ChartBox
+var theme ebcanvas.Theme
 
 type App struct{}
 
@@ -35,11 +37,12 @@ func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 type chartOptions struct {
-	top, bottom, left, right                                                               float64
-	barwidth, linewidth, linespacing, dotsize, textsize, piesize, ty, frameOp, opacity     float64
-	bgcolor, dcolor, labelcolor, valuecolor, chartitle, yaxfmt, yrange, fontname, valuefmt string
-	xlabel                                                                                 int
-	zb, line, bar, hbar, scatter, area, pie, lego, dot, wbar, showtitle, showgrid          bool
+	top, bottom, left, right                                                                float64
+	barwidth, linewidth, linespacing, dotsize, textsize, piesize, ty, frameOp, opacity      float64
+	bgcolor, dcolor, labelcolor, valuecolor, chartitle, yaxfmt, yrange, fontname, valuefmt  string
+	colorscale, palette, theme                                                              string
+	xlabel                                                                                  int
+	zb, line, bar, hbar, scatter, area, pie, lego, dot, wbar, showtitle, showgrid, patterns bool
 }
 
 // perr prints a filename and message to stderr
@@ -47,6 +50,101 @@ func perr(msg, file string) {
 	io.WriteString(os.Stderr, file+": "+msg+"\n")
 }
 
+// checkcolors warns about colors in the options that are not understood
+func checkcolors() {
+	for _, c := range []struct{ flag, value string }{
+		{"color", opts.dcolor},
+		{"bgcolor", opts.bgcolor},
+		{"labelcolor", opts.labelcolor},
+		{"valuecolor", opts.valuecolor},
+	} {
+		if _, err := ebcanvas.ParseColor(c.value); err != nil {
+			fmt.Fprintf(os.Stderr, "-%s: %v\n", c.flag, err)
+		}
+	}
+	if _, ok := ebcanvas.PaletteColors(opts.palette); opts.palette != "" && !ok {
+		fmt.Fprintf(os.Stderr, "-palette: unknown palette %q\n", opts.palette)
+	}
+	if _, ok := ebcanvas.PaletteColors(opts.colorscale); ok {
+		return
+	}
+	for _, s := range colorlist(opts.colorscale) {
+		if _, err := ebcanvas.ParseColor(s); err != nil {
+			fmt.Fprintf(os.Stderr, "-colorscale: %v\n", err)
+		}
+	}
+}
+
+// colorlist splits a list of colors separated by spaces or commas,
+// keeping the arguments of color functions like "rgb(10,20,30)" together
+func colorlist(s string) []string {
+	var colors []string
+	depth, start := 0, 0
+	for i, r := range s + " " {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0 && (r == ' ' || r == ','):
+			if i > start {
+				colors = append(colors, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return colors
+}
+
+// valuescale makes a color scale from the minimum to the maximum value,
+// through the colors in the list, or the colors of a named palette
+func valuescale(list string, minv, maxv float64) *ebcanvas.ColorScale {
+	names := colorlist(list)
+	if len(names) == 0 {
+		return nil
+	}
+	if p, ok := ebcanvas.PaletteColors(list); ok {
+		return ebcanvas.NewSequential(minv, maxv, p...)
+	}
+	colors := make([]color.NRGBA, len(names))
+	for i, name := range names {
+		colors[i] = ebcanvas.ColorLookup(name)
+	}
+	return ebcanvas.NewSequential(minv, maxv, colors...)
+}
+
+// themedefaults loads the theme, and uses its colors and text size for options not given
+func themedefaults() {
+	var err error
+	if theme, err = ebcanvas.LoadTheme(opts.theme); err != nil {
+		fmt.Fprintf(os.Stderr, "-theme: %v\n", err)
+	}
+	for _, o := range []struct {
+		s *string
+		c color.NRGBA
+	}{
+		{&opts.bgcolor, theme.Background},
+		{&opts.labelcolor, theme.Label},
+		{&opts.valuecolor, theme.Value},
+	} {
+		if *o.s == "" {
+			*o.s = ebcanvas.FormatRGB(o.c)
+		}
+	}
+	if opts.textsize <= 0 {
+		opts.textsize = float64(theme.TextSize)
+	}
+}
+
+// autocolor returns the color name, or for "auto", a gray that is readable against the background
+func autocolor(name, bgcolor string) string {
+	if name != "auto" {
+		return name
+	}
+	c := ebcanvas.ReadableColor(color.NRGBA{128, 128, 128, 255}, ebcanvas.ColorLookup(bgcolor), ebcanvas.ContrastAA)
+	return ebcanvas.FormatRGB(c)
+}
+
 // string to floating point
 func stof(s string) float64 {
 	v, err := strconv.ParseFloat(s, 64)
@@ -95,6 +193,7 @@ func gchart(screen *ebiten.Image) {
 
 	// Set the chart attributes
 	data.Zerobased = opts.zb
+	data.Patterns = opts.patterns
 	data.Top, data.Bottom = opts.top, opts.bottom
 	data.Left, data.Right = opts.left, opts.right
 
@@ -177,8 +276,12 @@ Options     Default               Description
 -scatter     false                make a scatter chart
 .....................................................................
 -color       "lightsteelblue"     data color
--labelcolor  "rgb(100,100,100)"   label color
--valuecolor  "rgb(128,0,0)"       value color
+-colorscale  ""                   color bars, dots and points by value: colors low to high, or a palette
+-palette     ""                   palette for pie and lego categories without a color ("": the theme's)
+-theme       "light"              theme: light, dark, or a theme file
+-bgcolor     ""                   background color ("": the theme's)
+-labelcolor  ""                   label color ("": the theme's, "auto": readable against the background)
+-valuecolor  ""                   value color ("": the theme's, "auto": readable against the background)
 -opacity     40                   opacity for area and wbar charts
 -frame       0                    frame opacity
 -font        ""                   specify font file (\"\": default)
@@ -195,7 +298,7 @@ Options     Default               Description
 -linewidth   0.25                 line width
 -ls          2                    line spacing
 -piesize     20                   pie chart radius
--textsize    1.5                  text size
+-textsize    0                    text size (0: the theme's)
 .....................................................................
 -chartitle   ""                   chart title
 -ty          5                    title position relative to the top
@@ -205,6 +308,7 @@ Options     Default               Description
 -yrange      ""                   y axis range (min,max,step)
 .....................................................................
 -grid        false                show y axis grid
+-patterns    false                fill pie and lego categories with patterns
 -title       false                show the title
 -zero        true                 zero minumum
 ......................................................................
@@ -232,7 +336,7 @@ func main() {
 	flag.Float64Var(&opts.linewidth, "linewidth", 0.25, "line width")
 	flag.Float64Var(&opts.linespacing, "ls", opts.barwidth*4, "line spacing")
 	flag.Float64Var(&opts.piesize, "piesize", 20, "pie chart radius")
-	flag.Float64Var(&opts.textsize, "textsize", 1.5, "text size")
+	flag.Float64Var(&opts.textsize, "textsize", 0, "text size (0: the theme's)")
 	// canvas sizes
 	flag.IntVar(&screenWidth, "w", 1000, "canvas width")
 	flag.IntVar(&screenHeight, "h", 1000, "canvas height")
@@ -250,18 +354,26 @@ func main() {
 	flag.StringVar(&opts.yaxfmt, "yfmt", "%v", "yaxis format (\"\" no y axis)")
 	// colors and opacities
 	flag.StringVar(&opts.dcolor, "color", "lightsteelblue", "color")
-	flag.StringVar(&opts.bgcolor, "bgcolor", "white", "background color")
+	flag.StringVar(&opts.colorscale, "colorscale", "", "colors for values, low to high, or a palette")
+	flag.StringVar(&opts.palette, "palette", "", "palette for categories without a color")
+	flag.StringVar(&opts.theme, "theme", "light", "theme: light, dark, or a theme file")
+	flag.StringVar(&opts.bgcolor, "bgcolor", "", "background color")
 	flag.StringVar(&opts.fontname, "font", "", "font name")
-	flag.StringVar(&opts.labelcolor, "labelcolor", "rgb(100,100,100)", "label color")
-	flag.StringVar(&opts.valuecolor, "valuecolor", "rgb(128,100,0)", "value color")
+	flag.StringVar(&opts.labelcolor, "labelcolor", "", "label color")
+	flag.StringVar(&opts.valuecolor, "valuecolor", "", "value color")
 	flag.Float64Var(&opts.frameOp, "frame", 0, "frame opacity (0: no frame)")
 	flag.Float64Var(&opts.opacity, "opacity", 40, "% opacity for area and wbar charts")
 	// on-off flags
 	flag.BoolVar(&opts.showtitle, "title", true, "show the title")
 	flag.BoolVar(&opts.showgrid, "grid", false, "show y axis grid")
+	flag.BoolVar(&opts.patterns, "patterns", false, "fill pie and lego categories with patterns")
 	flag.BoolVar(&opts.zb, "zero", true, "zero minumum")
 	flag.Usage = cmdUsage
 	flag.Parse()
+	themedefaults()
+	opts.labelcolor = autocolor(opts.labelcolor, opts.bgcolor)
+	opts.valuecolor = autocolor(opts.valuecolor, opts.bgcolor)
+	checkcolors()
 
 	var input io.Reader
 	var ferr, err error
@@ -285,6 +397,9 @@ func main() {
 		perr("unable to read ", infile)
 		os.Exit(2)
 	}
+	data.ColorScale = valuescale(opts.colorscale, data.Minvalue, data.Maxvalue)
+	data.Palette = opts.palette
+	data.Theme = &theme
 	// specify at least one of line, bar, hbar, scatter, area, pie, lego
 	if !(opts.line || opts.scatter || opts.bar || opts.dot || opts.wbar || opts.area || opts.hbar || opts.lego || opts.pie) {
 		perr("pick a chart type (-line, -bar, -hbar, -area, -scatter, -lego, -pie)", infile)
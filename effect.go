@@ -0,0 +1,179 @@
+package ebcanvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Effects
+
+// Effect is a drop shadow, outline and glow drawn around shapes or text,
+// using percent-based measures. Zero colors turn off each part.
+type Effect struct {
+	Shadow           color.NRGBA // shadow color
+	ShadowX, ShadowY float32     // shadow offset (positive is right and up)
+	Blur             float32     // shadow blur radius
+	Outline          color.NRGBA // outline color
+	OutlineWidth     float32     // outline width
+	Glow             color.NRGBA // glow color
+	GlowRadius       float32     // glow radius
+}
+
+// DropShadow makes a shadow offset by (dx, dy), blurred by the radius
+func DropShadow(dx, dy, blur float32, shadowcolor color.NRGBA) Effect {
+	return Effect{Shadow: shadowcolor, ShadowX: dx, ShadowY: dy, Blur: blur}
+}
+
+// Outline makes an outline of width w
+func Outline(w float32, outlinecolor color.NRGBA) Effect {
+	return Effect{Outline: outlinecolor, OutlineWidth: w}
+}
+
+// Glow makes a glow that fades out over the radius
+func Glow(r float32, glowcolor color.NRGBA) Effect {
+	return Effect{Glow: glowcolor, GlowRadius: r}
+}
+
+// scratch images, reused between calls
+const (
+	maskImage = iota
+	layerImage
+	silhouetteImage
+	blurImage1
+	blurImage2
+	outlineImage
+	cvdImage
+	nscratch
+)
+
+var scratchimages [nscratch]*ebiten.Image
+
+// scratch returns a cleared scratch image of at least the specified size
+func scratch(i, w, h int) *ebiten.Image {
+	s := scratchimages[i]
+	if s == nil || s.Bounds().Dx() < w || s.Bounds().Dy() < h {
+		if s != nil {
+			w, h = max(w, s.Bounds().Dx()), max(h, s.Bounds().Dy())
+			s.Deallocate()
+		}
+		s = ebiten.NewImage(w, h)
+		scratchimages[i] = s
+	}
+	s.Clear()
+	return s
+}
+
+// silhouette fills dst with the shape of src, in the color
+func silhouette(dst, src *ebiten.Image, c color.NRGBA) {
+	dst.Fill(c)
+	op := &ebiten.DrawImageOptions{Blend: ebiten.BlendDestinationIn}
+	dst.DrawImage(src, op)
+}
+
+// blur draws src on dst, blurred by the radius r (pixels) and offset by (dx, dy),
+// with copies of src reduced in size and spread by a Gaussian kernel
+func blur(dst, src *ebiten.Image, r, dx, dy float64) {
+	f := math.Max(1, r/4) // reduction, which keeps the kernel small
+	sb := src.Bounds()
+	sw, sh := int(math.Ceil(float64(sb.Dx())/f)), int(math.Ceil(float64(sb.Dy())/f))
+	small := scratch(blurImage1, sw, sh)
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(1/f, 1/f)
+	small.DrawImage(src, op)
+
+	sigma := r / f / 2
+	n := int(math.Ceil(2 * sigma))
+	weights := make([]float32, 2*n+1)
+	var sum float32
+	for i := range weights {
+		d := float64(i - n)
+		weights[i] = float32(math.Exp(-d * d / (2 * sigma * sigma)))
+		sum += weights[i]
+	}
+	// separable passes: horizontal into the second image, vertical back into the first
+	pass := func(dst, src *ebiten.Image, horizontal bool) {
+		for i, w := range weights {
+			op := &ebiten.DrawImageOptions{Blend: ebiten.BlendLighter}
+			if horizontal {
+				op.GeoM.Translate(float64(i-n), 0)
+			} else {
+				op.GeoM.Translate(0, float64(i-n))
+			}
+			op.ColorScale.Scale(w/sum, w/sum, w/sum, w/sum)
+			dst.DrawImage(src, op)
+		}
+	}
+	if n > 0 {
+		tmp := scratch(blurImage2, sw, sh)
+		pass(tmp, small.SubImage(image.Rect(0, 0, sw, sh)).(*ebiten.Image), true)
+		small.Clear()
+		pass(small, tmp.SubImage(image.Rect(0, 0, sw, sh)).(*ebiten.Image), false)
+	}
+	op = &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(f, f)
+	op.GeoM.Translate(dx, dy)
+	dst.DrawImage(small.SubImage(image.Rect(0, 0, sw, sh)).(*ebiten.Image), op)
+}
+
+// WithEffect draws the shapes made by draw, with the effect:
+// the shadow first, then the glow and outline, then the shapes.
+// Effects follow the shapes exactly, so they work for text as well as filled shapes.
+// draw must not call WithEffect.
+func (c *Canvas) WithEffect(e Effect, draw func(*Canvas)) {
+	b := c.Screen.Bounds()
+	w, h := b.Max.X, b.Max.Y
+	layer := scratch(layerImage, w, h)
+	m := *c
+	m.Screen = layer.SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+	draw(&m)
+	src := m.Screen
+
+	cw := float32(c.Width)
+	sil := scratch(silhouetteImage, w, h).SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+	if e.Shadow.A > 0 {
+		silhouette(sil, src, e.Shadow)
+		dx, dy := float64(pct(e.ShadowX, cw)), -float64(pct(e.ShadowY, cw))
+		if r := float64(pct(e.Blur, cw)); r >= 1 {
+			blur(c.Screen, sil, r, dx, dy)
+		} else {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(dx, dy)
+			c.Screen.DrawImage(sil, op)
+		}
+	}
+	if e.Glow.A > 0 {
+		silhouette(sil, src, e.Glow)
+		if r := float64(pct(e.GlowRadius, cw)); r >= 1 {
+			// twice, since blurring spreads the color thin
+			blur(c.Screen, sil, r, 0, 0)
+			blur(c.Screen, sil, r/2, 0, 0)
+		}
+	}
+	if e.Outline.A > 0 {
+		if r := float64(pct(e.OutlineWidth, cw)); r > 0 {
+			opaque := e.Outline
+			opaque.A = 255
+			silhouette(sil, src, opaque)
+			// grow the silhouette by drawing it around circles of the outline width,
+			// opaque so the copies do not build up, then apply the opacity of the color
+			out := scratch(outlineImage, w, h).SubImage(image.Rect(0, 0, w, h)).(*ebiten.Image)
+			n := int(math.Min(32, math.Max(8, math.Ceil(2*math.Pi*r/1.5))))
+			for _, rr := range []float64{r, r / 2} {
+				for i := 0; i < n; i++ {
+					s, co := math.Sincos(2 * math.Pi * float64(i) / float64(n))
+					op := &ebiten.DrawImageOptions{}
+					op.GeoM.Translate(rr*co, rr*s)
+					out.DrawImage(sil, op)
+				}
+			}
+			a := float32(e.Outline.A) / 255
+			op := &ebiten.DrawImageOptions{}
+			op.ColorScale.Scale(a, a, a, a)
+			c.Screen.DrawImage(out, op)
+		}
+	}
+	c.Screen.DrawImage(src, nil)
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/geom"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
@@ -339,34 +340,14 @@ func square(canvas *ebcanvas.Canvas, x, y, w float64, color string) {
 	canvas.Square(float32(x), float32(y), float32(w), ebcanvas.ColorLookup(color))
 }
 
-// pangles computes the points of a polygon based on a series of angles
-func pangles(cx, cy, r float64, angles []float64) ([]float32, []float32) {
-	px := make([]float32, len(angles))
-	py := make([]float32, len(angles))
-	aspect := float64(screenWidth) / float64(screenHeight)
-	for i, a := range angles {
-		t := a * (math.Pi / 180)
-		px[i] = float32(cx + (r * math.Cos(t)))
-		py[i] = float32(cy + ((r * aspect) * math.Sin(t)))
-	}
-	return px, py
-}
-
 // hexagon makes a filled hexagon centered at (cx, cy), size is the subscribed circle radius r
 func hexagon(canvas *ebcanvas.Canvas, cx, cy, r float64, color string) {
-	px, py := pangles(cx, cy, r, []float64{30, 90, 150, 210, 270, 330})
-	canvas.Polygon(px, py, ebcanvas.ColorLookup(color))
+	geom.Regular(canvas, float32(cx), float32(cy), float32(r), 6, 30).Fill(canvas, ebcanvas.ColorLookup(color))
 }
 
 // polylines makes a outlined hexagon, centered at (cx, cy), size is the subscribed circle radius r
 func polylines(canvas *ebcanvas.Canvas, cx, cy, r, lw float64, color string) {
-	px, py := pangles(cx, cy, r, []float64{30, 90, 150, 210, 270, 330})
-	lx := len(px) - 1
-	linewidth := float32(lw)
-	for i := 0; i < lx; i++ {
-		canvas.Line(px[i], py[i], px[i+1], py[i+1], linewidth, ebcanvas.ColorLookup(color))
-	}
-	canvas.Line(px[0], py[0], px[lx], py[lx], linewidth, ebcanvas.ColorLookup(color))
+	geom.Regular(canvas, float32(cx), float32(cy), float32(r), 6, 30).Stroke(canvas, float32(lw), ebcanvas.ColorLookup(color))
 }
 
 // legend makes the subtitle
@@ -0,0 +1,50 @@
+package gen
+
+import (
+	"math"
+
+	"github.com/ajstarks/ebcanvas/geom"
+	"github.com/ajstarks/ebcanvas/layout"
+)
+
+// FlowField is a field of directions given by noise, for tracing flowing lines
+type FlowField struct {
+	Noise *Noise
+	Scale float64 // noise frequency per percent (default 0.02)
+	Turns float64 // angle range, in full turns, over the noise range (default 1)
+	Z     float64 // depth in the noise, to animate the field
+}
+
+// NewFlowField makes a flow field from the seed
+func NewFlowField(seed int64) *FlowField {
+	return &FlowField{Noise: NewNoise(seed), Scale: 0.02, Turns: 1}
+}
+
+// Angle returns the direction of the field at (x,y), in radians
+func (f *FlowField) Angle(x, y float32) float64 {
+	s := f.Scale
+	if s == 0 {
+		s = 0.02
+	}
+	turns := f.Turns
+	if turns == 0 {
+		turns = 1
+	}
+	return f.Noise.Perlin3(float64(x)*s, float64(y)*s, f.Z) * turns * 2 * math.Pi
+}
+
+// Trace follows the field from the start for n steps of the specified length,
+// stopping if the line leaves the region
+func (f *FlowField) Trace(start geom.Point, step float32, n int, region layout.Rect) []geom.Point {
+	pts := []geom.Point{start}
+	p := start
+	for i := 0; i < n; i++ {
+		a := f.Angle(p.X, p.Y)
+		p = geom.Pt(p.X+step*float32(math.Cos(a)), p.Y+step*float32(math.Sin(a)))
+		if !p.In(region) {
+			break
+		}
+		pts = append(pts, p)
+	}
+	return pts
+}
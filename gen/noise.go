@@ -0,0 +1,168 @@
+package gen
+
+import "math"
+
+// Noise makes smooth pseudo-random values, in the range -1 to 1, that vary continuously with position
+type Noise struct {
+	perm [512]uint8
+}
+
+// NewNoise makes noise from the seed
+func NewNoise(seed int64) *Noise {
+	n := &Noise{}
+	r := New(seed)
+	p := make([]uint8, 256)
+	for i := range p {
+		p[i] = uint8(i)
+	}
+	r.Shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] })
+	for i := range n.perm {
+		n.perm[i] = p[i&255]
+	}
+	return n
+}
+
+// fade is the quintic smoothing curve of improved Perlin noise
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad2 returns the dot product of (x,y) with one of 8 gradient directions
+func grad2(h uint8, x, y float64) float64 {
+	switch h & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return x - y
+	case 2:
+		return -x + y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
+
+// grad3 returns the dot product of (x,y,z) with one of 12 gradient directions
+func grad3(h uint8, x, y, z float64) float64 {
+	h &= 15
+	u, v := x, y
+	if h >= 8 {
+		u = y
+	}
+	switch {
+	case h < 4:
+	case h == 12 || h == 14:
+		v = x
+	default:
+		v = z
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// floor returns the integer part of v, and the lattice cell (0-255) it is in
+func floor(v float64) (float64, int) {
+	f := math.Floor(v)
+	return f, int(f) & 255
+}
+
+// Perlin returns Perlin noise at (x,y)
+func (n *Noise) Perlin(x, y float64) float64 {
+	fx, xi := floor(x)
+	fy, yi := floor(y)
+	x, y = x-fx, y-fy
+	u, v := fade(x), fade(y)
+	p := &n.perm
+	a, b := int(p[xi])+yi, int(p[xi+1])+yi
+	return lerp(v,
+		lerp(u, grad2(p[a], x, y), grad2(p[b], x-1, y)),
+		lerp(u, grad2(p[a+1], x, y-1), grad2(p[b+1], x-1, y-1)))
+}
+
+// Perlin3 returns Perlin noise at (x,y,z); z is often time, to animate 2D noise
+func (n *Noise) Perlin3(x, y, z float64) float64 {
+	fx, xi := floor(x)
+	fy, yi := floor(y)
+	fz, zi := floor(z)
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+	p := &n.perm
+	a := int(p[xi]) + yi
+	aa, ab := int(p[a])+zi, int(p[a+1])+zi
+	b := int(p[xi+1]) + yi
+	ba, bb := int(p[b])+zi, int(p[b+1])+zi
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad3(p[aa], x, y, z), grad3(p[ba], x-1, y, z)),
+			lerp(u, grad3(p[ab], x, y-1, z), grad3(p[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad3(p[aa+1], x, y, z-1), grad3(p[ba+1], x-1, y, z-1)),
+			lerp(u, grad3(p[ab+1], x, y-1, z-1), grad3(p[bb+1], x-1, y-1, z-1))))
+}
+
+// skewing factors of 2D simplex noise
+var (
+	skew   = (math.Sqrt(3) - 1) / 2
+	unskew = (3 - math.Sqrt(3)) / 6
+)
+
+// Simplex returns simplex noise at (x,y), which has fewer directional artifacts than Perlin noise
+func (n *Noise) Simplex(x, y float64) float64 {
+	s := (x + y) * skew
+	fi, fj := math.Floor(x+s), math.Floor(y+s)
+	t := (fi + fj) * unskew
+	x0, y0 := x-(fi-t), y-(fj-t)
+	// the second corner of the triangle containing the point
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+	x1, y1 := x0-float64(i1)+unskew, y0-float64(j1)+unskew
+	x2, y2 := x0-1+2*unskew, y0-1+2*unskew
+	i, j := int(fi)&255, int(fj)&255
+	p := &n.perm
+	corner := func(h uint8, x, y float64) float64 {
+		t := 0.5 - x*x - y*y
+		if t < 0 {
+			return 0
+		}
+		t *= t
+		return t * t * grad2(h, x, y)
+	}
+	v := corner(p[i+int(p[j])], x0, y0) +
+		corner(p[i+i1+int(p[j+j1])], x1, y1) +
+		corner(p[i+1+int(p[j+1])], x2, y2)
+	return 70 * v
+}
+
+// Fractal sums octaves of Perlin noise at (x,y), each with frequency multiplied by lacunarity (usually 2)
+// and amplitude multiplied by gain (usually 0.5), for more detailed texture. The result is in the range -1 to 1.
+func (n *Noise) Fractal(x, y float64, octaves int, lacunarity, gain float64) float64 {
+	sum, amp, total := 0.0, 1.0, 0.0
+	for i := 0; i < octaves; i++ {
+		sum += amp * n.Perlin(x, y)
+		total += amp
+		x, y = x*lacunarity, y*lacunarity
+		amp *= gain
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
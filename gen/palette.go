@@ -0,0 +1,47 @@
+package gen
+
+import (
+	"image/color"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// Palette is a set of colors, picked at random in proportion to their weights
+type Palette struct {
+	Colors  []color.NRGBA
+	Weights []float64 // missing weights count as 1
+}
+
+// NewPalette makes a palette of equally weighted colors, specified as for ColorLookup
+func NewPalette(colors ...string) *Palette {
+	p := &Palette{}
+	for _, s := range colors {
+		p.Add(s, 1)
+	}
+	return p
+}
+
+// Add adds a color (specified as for ColorLookup) with the weight
+func (p *Palette) Add(s string, weight float64) *Palette {
+	for len(p.Weights) < len(p.Colors) {
+		p.Weights = append(p.Weights, 1)
+	}
+	p.Colors = append(p.Colors, ec.ColorLookup(s))
+	p.Weights = append(p.Weights, weight)
+	return p
+}
+
+// Pick returns a color from the palette; an empty palette returns black
+func (p *Palette) Pick(r *Rand) color.NRGBA {
+	if len(p.Colors) == 0 {
+		return color.NRGBA{0, 0, 0, 255}
+	}
+	w := make([]float64, len(p.Colors))
+	for i := range w {
+		w[i] = 1
+		if i < len(p.Weights) {
+			w[i] = p.Weights[i]
+		}
+	}
+	return p.Colors[r.Weighted(w)]
+}
@@ -0,0 +1,75 @@
+package gen
+
+import (
+	"math"
+
+	"github.com/ajstarks/ebcanvas/geom"
+	"github.com/ajstarks/ebcanvas/layout"
+)
+
+// Poisson returns points in the region, no two closer than the distance, filling it evenly
+// with a natural look (Bridson's algorithm); k is the number of candidates tried around each
+// point before giving up on it (30 is usual). Distances are in percent coordinates.
+func Poisson(r *Rand, region layout.Rect, dist float32, k int) []geom.Point {
+	if dist <= 0 || region.W <= 0 || region.H <= 0 {
+		return nil
+	}
+	// each cell of the grid holds at most one point
+	cell := dist / float32(math.Sqrt2)
+	cols := int(math.Ceil(float64(region.W / cell)))
+	rows := int(math.Ceil(float64(region.H / cell)))
+	grid := make([]int, cols*rows)
+	for i := range grid {
+		grid[i] = -1
+	}
+	cellof := func(p geom.Point) (int, int) {
+		c := min(int((p.X-region.X)/cell), cols-1)
+		r := min(int((p.Y-region.Y)/cell), rows-1)
+		return c, r
+	}
+	var points []geom.Point
+	var active []int
+	add := func(p geom.Point) {
+		c, row := cellof(p)
+		grid[row*cols+c] = len(points)
+		active = append(active, len(points))
+		points = append(points, p)
+	}
+	// fits reports whether p is in the region and far enough from the other points
+	fits := func(p geom.Point) bool {
+		if !p.In(region) {
+			return false
+		}
+		c, row := cellof(p)
+		for j := max(row-2, 0); j <= min(row+2, rows-1); j++ {
+			for i := max(c-2, 0); i <= min(c+2, cols-1); i++ {
+				if n := grid[j*cols+i]; n >= 0 && points[n].Dist(p) < dist {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	add(geom.Pt(r.Float(region.X, region.Right()), r.Float(region.Y, region.Top())))
+	for len(active) > 0 {
+		ai := r.Intn(len(active))
+		p := points[active[ai]]
+		found := false
+		for i := 0; i < k; i++ {
+			a := r.Angle()
+			d := r.Float(dist, 2*dist)
+			q := geom.Pt(p.X+d*float32(math.Cos(a)), p.Y+d*float32(math.Sin(a)))
+			if fits(q) {
+				add(q)
+				found = true
+				break
+			}
+		}
+		if !found {
+			active[ai] = active[len(active)-1]
+			active = active[:len(active)-1]
+		}
+	}
+	return points
+}
@@ -0,0 +1,139 @@
+package geom
+
+import (
+	"image/color"
+	"math"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// Quad is a quadratic Bezier curve from P0 to P2, with control point P1
+type Quad struct {
+	P0, P1, P2 Point
+}
+
+// Cubic is a cubic Bezier curve from P0 to P3, with control points P1 and P2
+type Cubic struct {
+	P0, P1, P2, P3 Point
+}
+
+// At returns the point at t (0-1) along the curve
+func (q Quad) At(t float32) Point {
+	a, b := q.P0.Lerp(q.P1, t), q.P1.Lerp(q.P2, t)
+	return a.Lerp(b, t)
+}
+
+// Cubic returns the equivalent cubic curve
+func (q Quad) Cubic() Cubic {
+	return Cubic{q.P0, q.P0.Lerp(q.P1, 2.0/3), q.P2.Lerp(q.P1, 2.0/3), q.P2}
+}
+
+// Flatten returns points along the curve, no further than tolerance from it
+func (q Quad) Flatten(tolerance float32) []Point {
+	return q.Cubic().Flatten(tolerance)
+}
+
+// Bounds returns the smallest rectangle containing the curve
+func (q Quad) Bounds() Rect {
+	return q.Cubic().Bounds()
+}
+
+// Fill draws the filled curve
+func (q Quad) Fill(c *ec.Canvas, fillcolor color.NRGBA) {
+	c.Curve(q.P0.X, q.P0.Y, q.P1.X, q.P1.Y, q.P2.X, q.P2.Y, fillcolor)
+}
+
+// Stroke draws the curve
+func (q Quad) Stroke(c *ec.Canvas, size float32, strokecolor color.NRGBA) {
+	c.StrokedCurve(q.P0.X, q.P0.Y, q.P1.X, q.P1.Y, q.P2.X, q.P2.Y, size, strokecolor)
+}
+
+// At returns the point at t (0-1) along the curve
+func (b Cubic) At(t float32) Point {
+	l, _ := b.split(t)
+	return l.P3
+}
+
+// split divides the curve at t, using de Casteljau's algorithm
+func (b Cubic) split(t float32) (Cubic, Cubic) {
+	p01, p12, p23 := b.P0.Lerp(b.P1, t), b.P1.Lerp(b.P2, t), b.P2.Lerp(b.P3, t)
+	p012, p123 := p01.Lerp(p12, t), p12.Lerp(p23, t)
+	m := p012.Lerp(p123, t)
+	return Cubic{b.P0, p01, p012, m}, Cubic{m, p123, p23, b.P3}
+}
+
+// flat reports whether the control points are within tolerance of the chord
+func (b Cubic) flat(tolerance float32) bool {
+	chord := Segment{b.P0, b.P3}
+	return chord.Dist(b.P1) <= tolerance && chord.Dist(b.P2) <= tolerance
+}
+
+// maxSplits limits the subdivision of curves when flattening
+const maxSplits = 16
+
+// Flatten returns points along the curve, no further than tolerance from it
+func (b Cubic) Flatten(tolerance float32) []Point {
+	pts := []Point{b.P0}
+	var flatten func(c Cubic, depth int)
+	flatten = func(c Cubic, depth int) {
+		if depth >= maxSplits || c.flat(tolerance) {
+			pts = append(pts, c.P3)
+			return
+		}
+		l, r := c.split(0.5)
+		flatten(l, depth+1)
+		flatten(r, depth+1)
+	}
+	flatten(b, 0)
+	return pts
+}
+
+// extrema returns the parameters (0-1) where a coordinate of the curve has a turning point
+func extrema(p0, p1, p2, p3 float32) []float32 {
+	// the derivative is a quadratic a*t^2 + b*t + c
+	a := float64(-p0 + 3*p1 - 3*p2 + p3)
+	b := float64(2 * (p0 - 2*p1 + p2))
+	c := float64(p1 - p0)
+	var roots []float64
+	switch {
+	case math.Abs(a) < 1e-12:
+		if b != 0 {
+			roots = append(roots, -c/b)
+		}
+	default:
+		d := b*b - 4*a*c
+		if d >= 0 {
+			sd := math.Sqrt(d)
+			roots = append(roots, (-b+sd)/(2*a), (-b-sd)/(2*a))
+		}
+	}
+	var t []float32
+	for _, r := range roots {
+		if r > 0 && r < 1 {
+			t = append(t, float32(r))
+		}
+	}
+	return t
+}
+
+// Bounds returns the smallest rectangle containing the curve
+func (b Cubic) Bounds() Rect {
+	pts := []Point{b.P0, b.P3}
+	for _, t := range extrema(b.P0.X, b.P1.X, b.P2.X, b.P3.X) {
+		pts = append(pts, b.At(t))
+	}
+	for _, t := range extrema(b.P0.Y, b.P1.Y, b.P2.Y, b.P3.Y) {
+		pts = append(pts, b.At(t))
+	}
+	return Bounds(pts...)
+}
+
+// Fill draws the filled curve
+func (b Cubic) Fill(c *ec.Canvas, fillcolor color.NRGBA) {
+	c.CubeCurve(b.P0.X, b.P0.Y, b.P1.X, b.P1.Y, b.P2.X, b.P2.Y, b.P3.X, b.P3.Y, fillcolor)
+}
+
+// Stroke draws the curve
+func (b Cubic) Stroke(c *ec.Canvas, size float32, strokecolor color.NRGBA) {
+	c.StrokedCubeCurve(b.P0.X, b.P0.Y, b.P1.X, b.P1.Y, b.P2.X, b.P2.Y, b.P3.X, b.P3.Y, size, strokecolor)
+}
@@ -0,0 +1,152 @@
+// Package geom provides points, segments, rectangles, polygons and Bezier curves
+// in the percent-based coordinates of the canvas, with methods to draw them.
+//
+// Computations are made in percent space: on a canvas that is not square,
+// distances and angles are those of the percent coordinates, not of the pixels.
+package geom
+
+import (
+	"image/color"
+	"math"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
+)
+
+// Point is a location in percent-based coordinates
+type Point struct {
+	X, Y float32
+}
+
+// Rect is a region with the lower left corner at (X,Y) and dimensions (W,H)
+type Rect = layout.Rect
+
+// Pt is shorthand for Point{x, y}
+func Pt(x, y float32) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add returns p+q
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns p-q
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Scale returns p multiplied by f
+func (p Point) Scale(f float32) Point {
+	return Point{p.X * f, p.Y * f}
+}
+
+// Dot returns the dot product of p and q
+func (p Point) Dot(q Point) float32 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross returns the z component of the cross product of p and q
+func (p Point) Cross(q Point) float32 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Len returns the distance of p from the origin
+func (p Point) Len() float32 {
+	return float32(math.Hypot(float64(p.X), float64(p.Y)))
+}
+
+// Dist returns the distance between p and q
+func (p Point) Dist(q Point) float32 {
+	return p.Sub(q).Len()
+}
+
+// Lerp returns the point a fraction t of the way from p to q
+func (p Point) Lerp(q Point, t float32) Point {
+	return Point{p.X + (q.X-p.X)*t, p.Y + (q.Y-p.Y)*t}
+}
+
+// Rotate returns p rotated counter-clockwise by the angle (degrees) around center
+func (p Point) Rotate(center Point, degrees float32) Point {
+	s, c := math.Sincos(float64(degrees) * math.Pi / 180)
+	d := p.Sub(center)
+	x := float64(d.X)*c - float64(d.Y)*s
+	y := float64(d.X)*s + float64(d.Y)*c
+	return Point{center.X + float32(x), center.Y + float32(y)}
+}
+
+// In reports whether p is within the rectangle
+func (p Point) In(r Rect) bool {
+	return r.Contains(p.X, p.Y)
+}
+
+// Coord shows the point and its coordinates, with a label
+func (p Point) Coord(c *ec.Canvas, size float32, label string, fillcolor color.NRGBA) {
+	c.Coord(p.X, p.Y, size, label, fillcolor)
+}
+
+// Bounds returns the smallest rectangle containing the points
+func Bounds(pts ...Point) Rect {
+	if len(pts) == 0 {
+		return Rect{}
+	}
+	x1, y1, x2, y2 := pts[0].X, pts[0].Y, pts[0].X, pts[0].Y
+	for _, p := range pts[1:] {
+		x1, y1 = min(x1, p.X), min(y1, p.Y)
+		x2, y2 = max(x2, p.X), max(y2, p.Y)
+	}
+	return Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}
+}
+
+// Corners returns the corners of a rectangle, counter-clockwise from the lower left
+func Corners(r Rect) Polygon {
+	return Polygon{{r.X, r.Y}, {r.X + r.W, r.Y}, {r.X + r.W, r.Y + r.H}, {r.X, r.Y + r.H}}
+}
+
+// Segment is the line between A and B
+type Segment struct {
+	A, B Point
+}
+
+// Len returns the length of the segment
+func (s Segment) Len() float32 {
+	return s.A.Dist(s.B)
+}
+
+// Mid returns the midpoint of the segment
+func (s Segment) Mid() Point {
+	return s.A.Lerp(s.B, 0.5)
+}
+
+// Intersect returns the point where two segments cross, if they do;
+// parallel segments do not cross
+func (s Segment) Intersect(o Segment) (Point, bool) {
+	d1, d2 := s.B.Sub(s.A), o.B.Sub(o.A)
+	den := d1.Cross(d2)
+	if den == 0 {
+		return Point{}, false
+	}
+	w := o.A.Sub(s.A)
+	t := w.Cross(d2) / den
+	u := w.Cross(d1) / den
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Point{}, false
+	}
+	return s.A.Lerp(s.B, t), true
+}
+
+// Dist returns the distance from p to the nearest point of the segment
+func (s Segment) Dist(p Point) float32 {
+	d := s.B.Sub(s.A)
+	l2 := d.Dot(d)
+	if l2 == 0 {
+		return p.Dist(s.A)
+	}
+	t := max(0, min(1, p.Sub(s.A).Dot(d)/l2))
+	return p.Dist(s.A.Lerp(s.B, t))
+}
+
+// Draw strokes the segment
+func (s Segment) Draw(c *ec.Canvas, size float32, strokecolor color.NRGBA) {
+	c.Line(s.A.X, s.A.Y, s.B.X, s.B.Y, size, strokecolor)
+}
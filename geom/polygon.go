@@ -0,0 +1,204 @@
+package geom
+
+import (
+	"image/color"
+	"math"
+	"sort"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// Polygon is a closed shape through its vertices
+type Polygon []Point
+
+// Regular returns a regular polygon of n sides inscribed in the circle of radius r centered at (cx,cy),
+// with the first vertex at the angle rotation (degrees);
+// the vertical radius is adjusted for the aspect ratio of the canvas, so the polygon looks regular.
+func Regular(c *ec.Canvas, cx, cy, r float32, n int, rotation float32) Polygon {
+	aspect := float64(c.Width) / float64(c.Height)
+	p := make(Polygon, n)
+	for i := range p {
+		s, co := math.Sincos((float64(rotation) + 360*float64(i)/float64(n)) * math.Pi / 180)
+		p[i] = Point{cx + float32(float64(r)*co), cy + float32(float64(r)*aspect*s)}
+	}
+	return p
+}
+
+// XY returns the coordinates of the vertices, as used by Canvas methods
+func (p Polygon) XY() ([]float32, []float32) {
+	x := make([]float32, len(p))
+	y := make([]float32, len(p))
+	for i, v := range p {
+		x[i], y[i] = v.X, v.Y
+	}
+	return x, y
+}
+
+// FromXY makes a polygon from coordinates
+func FromXY(x, y []float32) Polygon {
+	p := make(Polygon, min(len(x), len(y)))
+	for i := range p {
+		p[i] = Point{x[i], y[i]}
+	}
+	return p
+}
+
+// Bounds returns the smallest rectangle containing the polygon
+func (p Polygon) Bounds() Rect {
+	return Bounds(p...)
+}
+
+// Edges returns the sides of the polygon
+func (p Polygon) Edges() []Segment {
+	e := make([]Segment, len(p))
+	for i := range p {
+		e[i] = Segment{p[i], p[(i+1)%len(p)]}
+	}
+	return e
+}
+
+// Area returns the signed area: positive if the vertices are counter-clockwise
+func (p Polygon) Area() float32 {
+	var a float32
+	for i := range p {
+		a += p[i].Cross(p[(i+1)%len(p)])
+	}
+	return a / 2
+}
+
+// Perimeter returns the length of the sides
+func (p Polygon) Perimeter() float32 {
+	var l float32
+	for _, e := range p.Edges() {
+		l += e.Len()
+	}
+	return l
+}
+
+// Centroid returns the center of mass of the polygon
+// (the average of the vertices, if the area is zero)
+func (p Polygon) Centroid() Point {
+	if len(p) == 0 {
+		return Point{}
+	}
+	a := p.Area()
+	if a == 0 {
+		var sum Point
+		for _, v := range p {
+			sum = sum.Add(v)
+		}
+		return sum.Scale(1 / float32(len(p)))
+	}
+	var cx, cy float32
+	for i := range p {
+		q, r := p[i], p[(i+1)%len(p)]
+		f := q.Cross(r)
+		cx += (q.X + r.X) * f
+		cy += (q.Y + r.Y) * f
+	}
+	return Point{cx / (6 * a), cy / (6 * a)}
+}
+
+// Contains reports whether the point is inside the polygon (even-odd rule)
+func (p Polygon) Contains(pt Point) bool {
+	in := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Y > pt.Y) != (b.Y > pt.Y) && pt.X < (b.X-a.X)*(pt.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Hull returns the convex hull of the points, counter-clockwise
+func Hull(pts ...Point) Polygon {
+	if len(pts) < 3 {
+		return append(Polygon(nil), pts...)
+	}
+	s := append([]Point(nil), pts...)
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].X != s[j].X {
+			return s[i].X < s[j].X
+		}
+		return s[i].Y < s[j].Y
+	})
+	turn := func(o, a, b Point) float32 { return a.Sub(o).Cross(b.Sub(o)) }
+	h := make(Polygon, 0, 2*len(s))
+	for _, p := range s { // lower hull
+		for len(h) >= 2 && turn(h[len(h)-2], h[len(h)-1], p) <= 0 {
+			h = h[:len(h)-1]
+		}
+		h = append(h, p)
+	}
+	lower := len(h) + 1
+	for i := len(s) - 2; i >= 0; i-- { // upper hull
+		p := s[i]
+		for len(h) >= lower && turn(h[len(h)-2], h[len(h)-1], p) <= 0 {
+			h = h[:len(h)-1]
+		}
+		h = append(h, p)
+	}
+	return h[:len(h)-1]
+}
+
+// Hull returns the convex hull of the polygon
+func (p Polygon) Hull() Polygon {
+	return Hull(p...)
+}
+
+// miterLimit limits the length of offset corners, as a multiple of the offset
+const miterLimit = 4.0
+
+// Offset returns the polygon grown outward by d (or shrunk, if d is negative),
+// moving each side parallel to itself, with mitered corners
+func (p Polygon) Offset(d float32) Polygon {
+	n := len(p)
+	if n < 3 {
+		return append(Polygon(nil), p...)
+	}
+	if p.Area() < 0 {
+		d = -d // the outward normals of clockwise polygons are on the other side
+	}
+	normal := func(a, b Point) Point {
+		e := b.Sub(a)
+		l := e.Len()
+		if l == 0 {
+			return Point{}
+		}
+		return Point{e.Y / l, -e.X / l}
+	}
+	o := make(Polygon, n)
+	for i := range p {
+		prev, next := p[(i+n-1)%n], p[(i+1)%n]
+		n1, n2 := normal(prev, p[i]), normal(p[i], next)
+		m := n1.Add(n2)
+		k := 1 + n1.Dot(n2)
+		if k < 2/(miterLimit*miterLimit) {
+			k = 2 / (miterLimit * miterLimit)
+		}
+		o[i] = p[i].Add(m.Scale(d / k))
+	}
+	return o
+}
+
+// Fill draws the filled polygon
+func (p Polygon) Fill(c *ec.Canvas, fillcolor color.NRGBA) {
+	x, y := p.XY()
+	c.Polygon(x, y, fillcolor)
+}
+
+// Stroke draws the outline of the polygon
+func (p Polygon) Stroke(c *ec.Canvas, size float32, strokecolor color.NRGBA) {
+	if len(p) < 2 {
+		return
+	}
+	x, y := append(p[:len(p):len(p)], p[0]).XY()
+	c.Polyline(x, y, size, strokecolor)
+}
+
+// Polyline draws the open path through the points
+func Polyline(c *ec.Canvas, pts []Point, size float32, strokecolor color.NRGBA) {
+	x, y := Polygon(pts).XY()
+	c.Polyline(x, y, size, strokecolor)
+}
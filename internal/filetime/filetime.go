@@ -0,0 +1,31 @@
+// Package filetime reports when files were modified, for programs that reload their input when it changes
+package filetime
+
+import (
+	"os"
+	"time"
+)
+
+// Mod returns the modification time of a file
+func Mod(filename string) (time.Time, error) {
+	s, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.ModTime(), nil
+}
+
+// Latest returns the latest modification time of the files
+func Latest(filenames ...string) (time.Time, error) {
+	var last time.Time
+	for _, f := range filenames {
+		t, err := Mod(f)
+		if err != nil {
+			return last, err
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last, nil
+}
@@ -0,0 +1,221 @@
+// Package layout divides a percent-based canvas region into rows, columns and grids.
+//
+// Regions are rectangles with the lower left corner at (X,Y) and dimensions (W,H),
+// in the canvas coordinate system (0-100, y increasing up), so layouts follow
+// the canvas when the window is resized.
+package layout
+
+import ec "github.com/ajstarks/ebcanvas"
+
+// Rect is a region with lower left corner at (X,Y) and dimensions (W,H)
+type Rect struct {
+	X, Y, W, H float32
+}
+
+// Full is the whole canvas
+var Full = Rect{0, 0, 100, 100}
+
+// Size is the size of a row or column: either a fixed percentage of the canvas,
+// or a weighted share of the space left over by the fixed sizes
+type Size struct {
+	Value float32
+	Fixed bool
+}
+
+// Fixed is a size in percent
+func Fixed(v float32) Size {
+	return Size{Value: v, Fixed: true}
+}
+
+// Flex is a proportional share of the remaining space
+func Flex(weight float32) Size {
+	return Size{Value: weight}
+}
+
+// Even returns n equal proportional sizes, or none if n <= 0
+func Even(n int) []Size {
+	if n <= 0 {
+		return nil
+	}
+	s := make([]Size, n)
+	for i := range s {
+		s[i] = Flex(1)
+	}
+	return s
+}
+
+// Align places content within a region
+type Align int
+
+const (
+	Center Align = iota
+	Top
+	Bottom
+	Left
+	Right
+	TopLeft
+	TopRight
+	BottomLeft
+	BottomRight
+)
+
+// Center returns the center point of the region
+func (r Rect) Center() (float32, float32) {
+	return r.X + r.W/2, r.Y + r.H/2
+}
+
+// TopLeft returns the upper left corner of the region
+func (r Rect) TopLeft() (float32, float32) {
+	return r.X, r.Y + r.H
+}
+
+// Right returns the x coordinate of the right edge
+func (r Rect) Right() float32 {
+	return r.X + r.W
+}
+
+// Top returns the y coordinate of the top edge
+func (r Rect) Top() float32 {
+	return r.Y + r.H
+}
+
+// Contains reports whether (x,y) is within the region
+func (r Rect) Contains(x, y float32) bool {
+	return x >= r.X && x <= r.X+r.W && y >= r.Y && y <= r.Y+r.H
+}
+
+// Union returns the smallest region covering r and o
+func (r Rect) Union(o Rect) Rect {
+	x1, y1 := min(r.X, o.X), min(r.Y, o.Y)
+	x2, y2 := max(r.X+r.W, o.X+o.W), max(r.Y+r.H, o.Y+o.H)
+	return Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}
+}
+
+// Intersect returns the region common to r and o, and whether they meet (touching edges meet)
+func (r Rect) Intersect(o Rect) (Rect, bool) {
+	x1, y1 := max(r.X, o.X), max(r.Y, o.Y)
+	x2, y2 := min(r.X+r.W, o.X+o.W), min(r.Y+r.H, o.Y+o.H)
+	if x2 < x1 || y2 < y1 {
+		return Rect{}, false
+	}
+	return Rect{X: x1, Y: y1, W: x2 - x1, H: y2 - y1}, true
+}
+
+// Inset returns the region with padding p on every side
+func (r Rect) Inset(p float32) Rect {
+	return r.Pad(p, p, p, p)
+}
+
+// Pad returns the region with padding on the top, right, bottom and left
+func (r Rect) Pad(top, right, bottom, left float32) Rect {
+	w := r.W - left - right
+	h := r.H - top - bottom
+	return Rect{X: r.X + left, Y: r.Y + bottom, W: max(w, 0), H: max(h, 0)}
+}
+
+// Place returns a region with dimensions (w,h), aligned within r
+func (r Rect) Place(w, h float32, a Align) Rect {
+	x := r.X + (r.W-w)/2
+	y := r.Y + (r.H-h)/2
+	switch a {
+	case Left, TopLeft, BottomLeft:
+		x = r.X
+	case Right, TopRight, BottomRight:
+		x = r.X + r.W - w
+	}
+	switch a {
+	case Top, TopLeft, TopRight:
+		y = r.Y + r.H - h
+	case Bottom, BottomLeft, BottomRight:
+		y = r.Y
+	}
+	return Rect{X: x, Y: y, W: w, H: h}
+}
+
+// Canvas returns a sub-canvas covering the region
+func (r Rect) Canvas(c *ec.Canvas) *ec.Canvas {
+	return c.Sub(r.X, r.Y, r.W, r.H)
+}
+
+// ClipCanvas returns a sub-canvas covering the region, clipped to it
+func (r Rect) ClipCanvas(c *ec.Canvas) *ec.Canvas {
+	return c.ClipSub(r.X, r.Y, r.W, r.H)
+}
+
+// divide splits length into the specified sizes, separated by gap
+func divide(length, gap float32, sizes []Size) []float32 {
+	n := len(sizes)
+	if n == 0 {
+		return nil
+	}
+	free := length - gap*float32(n-1)
+	var weights float32
+	for _, s := range sizes {
+		if s.Fixed {
+			free -= s.Value
+		} else {
+			weights += s.Value
+		}
+	}
+	free = max(free, 0)
+	d := make([]float32, n)
+	for i, s := range sizes {
+		switch {
+		case s.Fixed:
+			d[i] = s.Value
+		case weights > 0:
+			d[i] = free * s.Value / weights
+		}
+	}
+	return d
+}
+
+// Rows divides the region into rows from top to bottom, separated by gap
+func (r Rect) Rows(gap float32, sizes ...Size) []Rect {
+	d := divide(r.H, gap, sizes)
+	rows := make([]Rect, len(d))
+	y := r.Y + r.H
+	for i, h := range d {
+		y -= h
+		rows[i] = Rect{X: r.X, Y: y, W: r.W, H: h}
+		y -= gap
+	}
+	return rows
+}
+
+// Columns divides the region into columns from left to right, separated by gap
+func (r Rect) Columns(gap float32, sizes ...Size) []Rect {
+	d := divide(r.W, gap, sizes)
+	cols := make([]Rect, len(d))
+	x := r.X
+	for i, w := range d {
+		cols[i] = Rect{X: x, Y: r.Y, W: w, H: r.H}
+		x += w + gap
+	}
+	return cols
+}
+
+// Grid divides the region into equal cells, returned by row from the top left,
+// separated horizontally by hgap and vertically by vgap; there are no cells if either count is <= 0
+func (r Rect) Grid(nrows, ncols int, hgap, vgap float32) [][]Rect {
+	if nrows <= 0 || ncols <= 0 {
+		return nil
+	}
+	rows := r.Rows(vgap, Even(nrows)...)
+	cells := make([][]Rect, nrows)
+	for i, row := range rows {
+		cells[i] = row.Columns(hgap, Even(ncols)...)
+	}
+	return cells
+}
+
+// Cells divides the region into rows and columns of the specified sizes,
+// returned by row from the top left
+func (r Rect) Cells(hgap, vgap float32, rowsizes, colsizes []Size) [][]Rect {
+	rows := r.Rows(vgap, rowsizes...)
+	cells := make([][]Rect, len(rows))
+	for i, row := range rows {
+		cells[i] = row.Columns(hgap, colsizes...)
+	}
+	return cells
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/color"
+	"os"
+
+	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
+	"github.com/ajstarks/ebcanvas/turtle"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+type App struct{}
+
+func (a *App) Update() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyQ) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		os.Exit(0)
+	}
+	return nil
+}
+
+func (a *App) Layout(outsideWidth, outsideHeight int) (int, int) {
+	screenWidth, screenHeight = ebcanvas.DisplayScale(outsideWidth, outsideHeight)
+	return screenWidth, screenHeight
+}
+
+func (a *App) Draw(screen *ebiten.Image) {
+	canvas := new(ebcanvas.Canvas)
+	canvas.Screen = screen
+	canvas.Width = screenWidth
+	canvas.Height = screenHeight
+	lsystems(canvas)
+}
+
+// figure is an L-system with its drawing parameters
+type figure struct {
+	name    string
+	system  turtle.LSystem
+	n       int
+	angle   float32
+	heading float32
+	color   color.NRGBA
+	program string
+}
+
+var (
+	screenWidth  = 1000
+	screenHeight = 1000
+	bgcolor      = color.NRGBA{250, 250, 245, 255}
+	labelcolor   = color.NRGBA{50, 50, 50, 255}
+	figures      = []figure{
+		{
+			name:   "Koch snowflake",
+			system: turtle.LSystem{Axiom: "F--F--F", Rules: map[rune]string{'F': "F+F--F+F"}},
+			n:      4, angle: 60, heading: 0,
+			color: color.NRGBA{0, 0, 128, 255},
+		},
+		{
+			name:   "Dragon curve",
+			system: turtle.LSystem{Axiom: "F", Rules: map[rune]string{'F': "F+G", 'G': "F-G"}},
+			n:      12, angle: 90, heading: 0,
+			color: color.NRGBA{128, 0, 0, 255},
+		},
+		{
+			name:   "Sierpinski triangle",
+			system: turtle.LSystem{Axiom: "F-G-G", Rules: map[rune]string{'F': "F-G+F+G-F", 'G': "GG"}},
+			n:      6, angle: 120, heading: 0,
+			color: color.NRGBA{128, 0, 128, 255},
+		},
+		{
+			name:   "Fractal plant",
+			system: turtle.LSystem{Axiom: "X", Rules: map[rune]string{'X': "F+[[X]-X]-F[-FX]+X", 'F': "FF"}},
+			n:      6, angle: 25, heading: 65,
+			color: color.NRGBA{0, 100, 0, 255},
+		},
+	}
+)
+
+// lsystems draws the figures in a grid
+func lsystems(canvas *ebcanvas.Canvas) {
+	canvas.Background(bgcolor)
+	cells := layout.Rect{X: 5, Y: 5, W: 90, H: 90}.Grid(2, 2, 5, 5)
+	i := 0
+	for _, row := range cells {
+		for _, cell := range row {
+			f := figures[i]
+			parts := cell.Rows(1, layout.Fixed(4), layout.Flex(1))
+			label, area := parts[0], parts[1]
+			canvas.CText(label.X+label.W/2, label.Y+1, 2.5, f.name, labelcolor)
+			x, y, step := turtle.Fit(canvas, area, f.program, f.angle, f.heading)
+			t := turtle.New(canvas)
+			t.X, t.Y, t.Heading = x, y, f.heading
+			t.Pen(f.color, 0.15)
+			t.Run(f.program, step, f.angle)
+			i++
+		}
+	}
+}
+
+func main() {
+	flag.IntVar(&screenWidth, "width", 1000, "canvas width")
+	flag.IntVar(&screenHeight, "height", 1000, "canvas height")
+	flag.Parse()
+
+	for i := range figures {
+		figures[i].program = figures[i].system.Expand(figures[i].n)
+	}
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle("lsystem")
+	if err := ebiten.RunGame(&App{}); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,150 @@
+package ebcanvas
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Named palettes
+
+// palettedata holds the built-in palettes as hex colors:
+// the ColorBrewer schemes (https://colorbrewer2.org) at their largest size,
+// the matplotlib perceptual maps sampled at 11 points (10 for cividis),
+// Tableau 10, and the Okabe-Ito colorblind-safe set, with black last
+var palettedata = map[string]string{
+	// ColorBrewer qualitative
+	"accent":  "7fc97f beaed4 fdc086 ffff99 386cb0 f0027f bf5b17 666666",
+	"dark2":   "1b9e77 d95f02 7570b3 e7298a 66a61e e6ab02 a6761d 666666",
+	"paired":  "a6cee3 1f78b4 b2df8a 33a02c fb9a99 e31a1c fdbf6f ff7f00 cab2d6 6a3d9a ffff99 b15928",
+	"pastel1": "fbb4ae b3cde3 ccebc5 decbe4 fed9a6 ffffcc e5d8bd fddaec f2f2f2",
+	"pastel2": "b3e2cd fdcdac cbd5e8 f4cae4 e6f5c9 fff2ae f1e2cc cccccc",
+	"set1":    "e41a1c 377eb8 4daf4a 984ea3 ff7f00 ffff33 a65628 f781bf 999999",
+	"set2":    "66c2a5 fc8d62 8da0cb e78ac3 a6d854 ffd92f e5c494 b3b3b3",
+	"set3":    "8dd3c7 ffffb3 bebada fb8072 80b1d3 fdb462 b3de69 fccde5 d9d9d9 bc80bd ccebc5 ffed6f",
+	// ColorBrewer sequential
+	"blues":   "f7fbff deebf7 c6dbef 9ecae1 6baed6 4292c6 2171b5 08519c 08306b",
+	"greens":  "f7fcf5 e5f5e0 c7e9c0 a1d99b 74c476 41ab5d 238b45 006d2c 00441b",
+	"greys":   "ffffff f0f0f0 d9d9d9 bdbdbd 969696 737373 525252 252525 000000",
+	"oranges": "fff5eb fee6ce fdd0a2 fdae6b fd8d3c f16913 d94801 a63603 7f2704",
+	"purples": "fcfbfd efedf5 dadaeb bcbddc 9e9ac8 807dba 6a51a3 54278f 3f007d",
+	"reds":    "fff5f0 fee0d2 fcbba1 fc9272 fb6a4a ef3b2c cb181d a50f15 67000d",
+	"bugn":    "f7fcfd e5f5f9 ccece6 99d8c9 66c2a4 41ae76 238b45 006d2c 00441b",
+	"bupu":    "f7fcfd e0ecf4 bfd3e6 9ebcda 8c96c6 8c6bb1 88419d 810f7c 4d004b",
+	"gnbu":    "f7fcf0 e0f3db ccebc5 a8ddb5 7bccc4 4eb3d3 2b8cbe 0868ac 084081",
+	"orrd":    "fff7ec fee8c8 fdd49e fdbb84 fc8d59 ef6548 d7301f b30000 7f0000",
+	"pubu":    "fff7fb ece7f2 d0d1e6 a6bddb 74a9cf 3690c0 0570b0 045a8d 023858",
+	"pubugn":  "fff7fb ece2f0 d0d1e6 a6bddb 67a9cf 3690c0 02818a 016c59 014636",
+	"purd":    "f7f4f9 e7e1ef d4b9da c994c7 df65b0 e7298a ce1256 980043 67001f",
+	"rdpu":    "fff7f3 fde0dd fcc5c0 fa9fb5 f768a1 dd3497 ae017e 7a0177 49006a",
+	"ylgn":    "ffffe5 f7fcb9 d9f0a3 addd8e 78c679 41ab5d 238443 006837 004529",
+	"ylgnbu":  "ffffd9 edf8b1 c7e9b4 7fcdbb 41b6c4 1d91c0 225ea8 253494 081d58",
+	"ylorbr":  "ffffe5 fff7bc fee391 fec44f fe9929 ec7014 cc4c02 993404 662506",
+	"ylorrd":  "ffffcc ffeda0 fed976 feb24c fd8d3c fc4e2a e31a1c bd0026 800026",
+	// ColorBrewer diverging
+	"brbg":     "543005 8c510a bf812d dfc27d f6e8c3 f5f5f5 c7eae5 80cdc1 35978f 01665e 003c30",
+	"piyg":     "8e0152 c51b7d de77ae f1b6da fde0ef f7f7f7 e6f5d0 b8e186 7fbc41 4d9221 276419",
+	"prgn":     "40004b 762a83 9970ab c2a5cf e7d4e8 f7f7f7 d9f0d3 a6dba0 5aae61 1b7837 00441b",
+	"puor":     "7f3b08 b35806 e08214 fdb863 fee0b6 f7f7f7 d8daeb b2abd2 8073ac 542788 2d004b",
+	"rdbu":     "67001f b2182b d6604d f4a582 fddbc7 f7f7f7 d1e5f0 92c5de 4393c3 2166ac 053061",
+	"rdgy":     "67001f b2182b d6604d f4a582 fddbc7 ffffff e0e0e0 bababa 878787 4d4d4d 1a1a1a",
+	"rdylbu":   "a50026 d73027 f46d43 fdae61 fee090 ffffbf e0f3f8 abd9e9 74add1 4575b4 313695",
+	"rdylgn":   "a50026 d73027 f46d43 fdae61 fee08b ffffbf d9ef8b a6d96a 66bd63 1a9850 006837",
+	"spectral": "9e0142 d53e4f f46d43 fdae61 fee08b ffffbf e6f598 abdda4 66c2a5 3288bd 5e4fa2",
+	// matplotlib perceptual maps
+	"viridis": "440154 482475 414487 355f8d 2a788e 21918c 22a884 44bf70 7ad151 bddf26 fde725",
+	"magma":   "000004 140e36 3b0f70 641a80 8c2981 b73779 de4968 f7705c fe9f6d fecf92 fcfdbf",
+	"inferno": "000004 160b39 420a68 6a176e 932667 bc3754 dd513a f37819 fca50a f6d746 fcffa4",
+	"plasma":  "0d0887 41049d 6a00a8 8f0da4 b12a90 cc4778 e16462 f2844b fca636 fcce25 f0f921",
+	"cividis": "00224e 123570 3b496c 575d6d 707173 8a8779 a69d75 c4b56c e4cf5b fee838",
+	// Tableau and Okabe-Ito
+	"tableau10": "4e79a7 f28e2b e15759 76b7b2 59a14f edc948 b07aa1 ff9da7 9c755f bab0ac",
+	"okabeito":  "e69f00 56b4e9 009e73 f0e442 0072b2 d55e00 cc79a7 000000",
+}
+
+// palettes holds the palettes, by lower case name
+var palettes = readpalettes()
+
+// readpalettes converts the built-in palettes from hex
+func readpalettes() map[string][]color.NRGBA {
+	p := map[string][]color.NRGBA{}
+	for name, hex := range palettedata {
+		digits := strings.Fields(hex)
+		colors := make([]color.NRGBA, len(digits))
+		for i, d := range digits {
+			colors[i], _ = parsehex(d)
+		}
+		p[name] = colors
+	}
+	return p
+}
+
+// RegisterPalette adds a palette, or replaces the palette with the same name
+func RegisterPalette(name string, colors ...color.NRGBA) {
+	palettes[strings.ToLower(name)] = colors
+}
+
+// PaletteColors returns the colors of a named palette; names are not case sensitive
+func PaletteColors(name string) ([]color.NRGBA, bool) {
+	colors, ok := palettes[strings.ToLower(name)]
+	return colors, ok
+}
+
+// PaletteScale returns a color scale through the colors of a named palette, from 0 to 1
+func PaletteScale(name string) (*ColorScale, bool) {
+	colors, ok := PaletteColors(name)
+	if !ok || len(colors) == 0 {
+		return nil, false
+	}
+	return NewSequential(0, 1, colors...), true
+}
+
+// PaletteNames returns the names of the palettes, sorted
+func PaletteNames() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parsepalette reads a palette color: "name:t", a position (0-1, or a percentage) along the palette,
+// or "name[i]", the i-th color (counting from 0)
+func parsepalette(s string) (color.NRGBA, error) {
+	black := color.NRGBA{0, 0, 0, 255}
+	if n := strings.IndexByte(s, ':'); n >= 0 {
+		name := strings.TrimSpace(s[:n])
+		ps, ok := PaletteScale(name)
+		if !ok {
+			return black, fmt.Errorf("unknown palette %q", name)
+		}
+		t, _, err := number(strings.TrimSpace(s[n+1:]), 1)
+		if err != nil {
+			return black, err
+		}
+		if t < 0 || t > 1 {
+			return black, fmt.Errorf("position %q out of range 0-1 (0%%-100%%)", s[n+1:])
+		}
+		return ps.Map(t), nil
+	}
+	n := strings.IndexByte(s, '[')
+	if n < 0 || !strings.HasSuffix(s, "]") {
+		return black, fmt.Errorf("want palette:position or palette[index]")
+	}
+	name := strings.TrimSpace(s[:n])
+	colors, ok := PaletteColors(name)
+	if !ok {
+		return black, fmt.Errorf("unknown palette %q", name)
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(s[n+1 : len(s)-1]))
+	if err != nil {
+		return black, fmt.Errorf("bad index %q", s[n+1:len(s)-1])
+	}
+	if i < 0 || i >= len(colors) {
+		return black, fmt.Errorf("index %d out of range 0-%d", i, len(colors)-1)
+	}
+	return colors[i], nil
+}
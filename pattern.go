@@ -0,0 +1,153 @@
+package ebcanvas
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Pattern fills
+
+type patternKind int
+
+const (
+	hatchPattern patternKind = iota
+	crossPattern
+	dotPattern
+	imagePattern
+)
+
+// Pattern is a repeating fill of lines, dots, stripes or an image,
+// using percent-based measures
+type Pattern struct {
+	kind       patternKind
+	Angle      float32     // rotation, degrees counter-clockwise
+	Spacing    float32     // distance between repeats (for images, the tile width)
+	Size       float32     // line width, or dot radius
+	Color      color.NRGBA // color of the lines or dots
+	Background color.NRGBA // color between the lines or dots (zero is transparent)
+	Image      image.Image // tile of image patterns
+}
+
+// Hatch makes parallel lines at angle (degrees), spaced apart, with width size
+func Hatch(angle, spacing, size float32, linecolor color.NRGBA) Pattern {
+	return Pattern{kind: hatchPattern, Angle: angle, Spacing: spacing, Size: size, Color: linecolor}
+}
+
+// CrossHatch makes two sets of perpendicular lines, the first at angle (degrees)
+func CrossHatch(angle, spacing, size float32, linecolor color.NRGBA) Pattern {
+	return Pattern{kind: crossPattern, Angle: angle, Spacing: spacing, Size: size, Color: linecolor}
+}
+
+// Dots makes a grid of dots of radius r, spaced apart
+func Dots(spacing, r float32, dotcolor color.NRGBA) Pattern {
+	return Pattern{kind: dotPattern, Spacing: spacing, Size: r, Color: dotcolor}
+}
+
+// Stripes makes alternating bands of width w, at angle (degrees)
+func Stripes(angle, w float32, color1, color2 color.NRGBA) Pattern {
+	return Pattern{kind: hatchPattern, Angle: angle, Spacing: w * 2, Size: w, Color: color1, Background: color2}
+}
+
+// Tiled repeats an image, scaled to width w
+func Tiled(img image.Image, w float32) Pattern {
+	return Pattern{kind: imagePattern, Spacing: w, Image: img}
+}
+
+// tilekey identifies a rendered pattern tile
+type tilekey struct {
+	kind   patternKind
+	period int
+	size   float32
+	fg, bg color.NRGBA
+	img    image.Image
+}
+
+// maximum number of cached tiles
+const maxtiles = 64
+
+var tiles = map[tilekey]*ebiten.Image{}
+
+// tile returns the image repeated by the pattern, and the pixel length of one repeat
+func (p Pattern) tile(cw float32) (*ebiten.Image, float32) {
+	period := pct(p.Spacing, cw)
+	k := tilekey{kind: p.kind, fg: p.Color, bg: p.Background}
+	if p.kind == imagePattern {
+		k.img = p.Image
+	} else {
+		// lines and dots are drawn in tiles of whole pixels, so they repeat without seams
+		k.period = max(int(math.Round(float64(period))), 2)
+		k.size = pct(p.Size, cw)
+		period = float32(k.period)
+	}
+	if t, ok := tiles[k]; ok {
+		return t, period
+	}
+	if len(tiles) >= maxtiles {
+		for key, t := range tiles {
+			t.Deallocate()
+			delete(tiles, key)
+		}
+	}
+	var t *ebiten.Image
+	switch p.kind {
+	case imagePattern:
+		t = ebiten.NewImageFromImage(p.Image)
+	default:
+		n := float32(k.period)
+		t = ebiten.NewImage(k.period, k.period)
+		t.Fill(p.Background)
+		switch p.kind {
+		case hatchPattern:
+			vector.FillRect(t, 0, (n-k.size)/2, n, k.size, p.Color, true)
+		case crossPattern:
+			vector.FillRect(t, 0, (n-k.size)/2, n, k.size, p.Color, true)
+			vector.FillRect(t, (n-k.size)/2, 0, k.size, n, p.Color, true)
+		case dotPattern:
+			vector.FillCircle(t, n/2, n/2, k.size, p.Color, true)
+		}
+	}
+	tiles[k] = t
+	return t, period
+}
+
+// FillPattern fills the shapes made by draw with the pattern.
+// The shapes, drawn on the canvas passed to draw, make a mask:
+// their colors are ignored, except for opacity.
+// The pattern is aligned to the origin of the canvas, so adjacent shapes line up.
+// draw must not call FillPattern.
+func (c *Canvas) FillPattern(p Pattern, draw func(*Canvas)) {
+	if p.Spacing <= 0 || (p.kind == imagePattern && p.Image == nil) {
+		return
+	}
+	// draw the shapes on a transparent mask the size of the screen
+	b := c.Screen.Bounds()
+	patternmask := scratch(maskImage, b.Max.X, b.Max.Y).SubImage(image.Rect(0, 0, b.Max.X, b.Max.Y)).(*ebiten.Image)
+	m := *c
+	m.Screen = patternmask
+	draw(&m)
+
+	// replace the mask with the pattern, keeping its opacity
+	cw := float32(c.Width)
+	t, period := p.tile(cw)
+	s := float32(t.Bounds().Dx()) / period // source pixels per screen pixel; images keep their aspect ratio
+	sin, cos := math.Sincos(float64(p.Angle) * math.Pi / 180)
+	src := func(x, y float32) (float32, float32) {
+		x, y = x-c.ox, y-c.oy
+		rx := float32(float64(x)*cos - float64(y)*sin)
+		ry := float32(float64(x)*sin + float64(y)*cos)
+		return rx * s, ry * s
+	}
+	x0, y0, x1, y1 := float32(b.Min.X), float32(b.Min.Y), float32(b.Max.X), float32(b.Max.Y)
+	vs := make([]ebiten.Vertex, 4)
+	for i, pt := range [4][2]float32{{x0, y0}, {x1, y0}, {x0, y1}, {x1, y1}} {
+		sx, sy := src(pt[0], pt[1])
+		vs[i] = ebiten.Vertex{DstX: pt[0], DstY: pt[1], SrcX: sx, SrcY: sy, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1}
+	}
+	op := &ebiten.DrawTrianglesOptions{Address: ebiten.AddressRepeat, Filter: ebiten.FilterLinear, Blend: ebiten.BlendSourceIn}
+	patternmask.DrawTriangles(vs, []uint16{0, 1, 2, 1, 2, 3}, t, op)
+	c.Screen.DrawImage(patternmask, nil)
+}
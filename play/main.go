@@ -8,14 +8,12 @@ import (
 	"os"
 
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/geom"
+	"github.com/ajstarks/ebcanvas/layout"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
-type Point struct {
-	X, Y float32
-}
-
 type App struct{}
 
 func (a *App) Update() error {
@@ -69,7 +67,6 @@ func play(screen *ebiten.Image) {
 	canvas.Width = screenWidth
 	canvas.Height = screenHeight
 
-	var colx float32
 	var lw float32 = 0.2
 	var labelsize float32 = 2
 	var wrap float32 = 15.0
@@ -81,12 +78,20 @@ func play(screen *ebiten.Image) {
 	// Title
 	canvas.Background(bgcolor)
 
-	colx = 20
+	// three columns of examples, in three rows with the label above each
+	cols := layout.Full.Columns(0, layout.Fixed(40), layout.Fixed(40), layout.Flex(1))
+	rows := layout.Rect{X: 0, Y: 7.5, W: 100, H: 75}.Rows(0, layout.Even(3)...)
+	colx, _ := cols[0].Center()
+	_, row1 := rows[0].Center()
+	_, row2 := rows[1].Center()
+	_, row3 := rows[2].Center()
+	const above = 10 // label distance above the row center
+
 	canvas.Text(10, 92, titlesize, "Ebiten Canvas API", labelcolor)
 	canvas.TextWrap(50, 96, 25, titlesize*.3, apimsg, labelcolor)
 
 	// Lines
-	canvas.CText(colx, 80, labelsize, "Line", labelcolor)
+	canvas.CText(colx, row1+above, labelsize, "Line", labelcolor)
 	canvas.Line(10, 70, colx+5, 65, lw, stcolor)
 	canvas.Coord(10, 70, subsize, "P0", labelcolor)
 	canvas.Coord(colx+5, 65, subsize, "P1", labelcolor)
@@ -101,15 +106,15 @@ func play(screen *ebiten.Image) {
 	tx := cx1 + (cx2-cx1)/2
 
 	// Circle
-	canvas.CText(cx1, 55, labelsize, "Circle", labelcolor)
-	canvas.Circle(cx1, 45, 4.5, fcolor)
-	canvas.Coord(cx1, 45, subsize, "center", labelcolor)
+	canvas.CText(cx1, row2+above, labelsize, "Circle", labelcolor)
+	canvas.Circle(cx1, row2, 4.5, fcolor)
+	canvas.Coord(cx1, row2, subsize, "center", labelcolor)
 
 	// Arc
-	canvas.CText(cx2, 55, labelsize, "Arc", labelcolor)
-	canvas.Arc(cx2, 45, 4.5, 0, 180, tcolor)
-	canvas.StrokedArc(cx2, 45, 4.5, 0, 180, lw, stcolor)
-	canvas.Coord(cx2, 45, subsize, "center", labelcolor)
+	canvas.CText(cx2, row2+above, labelsize, "Arc", labelcolor)
+	canvas.Arc(cx2, row2, 4.5, 0, 180, tcolor)
+	canvas.StrokedArc(cx2, row2, 4.5, 0, 180, lw, stcolor)
+	canvas.Coord(cx2, row2, subsize, "center", labelcolor)
 
 	// Wedge
 	//canvas.CText(cx2, 55, labelsize, "Wedge", labelcolor)
@@ -119,7 +124,7 @@ func play(screen *ebiten.Image) {
 	//canvas.Coord(cx2, 45, subsize, "center", labelcolor)
 
 	// Text
-	canvas.CText(colx, 30, labelsize, "Text", labelcolor)
+	canvas.CText(colx, row3+above, labelsize, "Text", labelcolor)
 	canvas.Text(tx, 25, subsize, "Begin-aligned", labelcolor)
 	canvas.Circle(tx, 25, subsize/4, labelcolor)
 	canvas.CText(tx, 20, subsize, "Centered", labelcolor)
@@ -131,24 +136,24 @@ func play(screen *ebiten.Image) {
 	canvas.RText(cx1, 5, 45, subsize, "Rotated", labelcolor)
 	canvas.Circle(cx1, 5, subsize/4, labelcolor)
 
+	colx, _ = cols[1].Center()
 	// Quadradic Bezier
-	start := Point{X: 45, Y: 65}
-	c1 := Point{X: 70, Y: 85}
-	end := Point{X: 70, Y: 65}
-	canvas.CText(60, 80, labelsize, "Quadratic Bezier Curve", labelcolor)
+	start := geom.Point{X: 45, Y: 65}
+	c1 := geom.Point{X: 70, Y: 85}
+	end := geom.Point{X: 70, Y: 65}
+	canvas.CText(colx, row1+above, labelsize, "Quadratic Bezier Curve", labelcolor)
 	canvas.StrokedCurve(start.X, start.Y, c1.X, c1.Y, end.X, end.Y, lw, stcolor)
 	canvas.Curve(start.X, start.Y, c1.X, c1.Y, end.X, end.Y, tcolor)
 	canvas.Coord(start.X, start.Y, subsize, "start", labelcolor)
 	canvas.Coord(c1.X, c1.Y, subsize, "control", labelcolor)
 	canvas.Coord(end.X, end.Y, subsize, "end", labelcolor)
 
-	colx += 40
 	// Cubic Bezier
-	start = Point{X: 45, Y: 40}
-	c1 = Point{X: 45, Y: 55}
-	c2 := Point{X: colx, Y: 50}
-	end = Point{X: 70, Y: 40}
-	canvas.CText(colx, 55, labelsize, "Cubic Bezier Curve", labelcolor)
+	start = geom.Point{X: 45, Y: 40}
+	c1 = geom.Point{X: 45, Y: 55}
+	c2 := geom.Point{X: colx, Y: 50}
+	end = geom.Point{X: 70, Y: 40}
+	canvas.CText(colx, row2+above, labelsize, "Cubic Bezier Curve", labelcolor)
 	canvas.StrokedCubeCurve(start.X, start.Y, c1.X, c1.Y, c2.X, c2.Y, end.X, end.Y, lw, sfcolor)
 	canvas.CubeCurve(start.X, start.Y, c1.X, c1.Y, c2.X, c2.Y, end.X, end.Y, fcolor)
 	canvas.Coord(start.X, start.Y, subsize, "start", labelcolor)
@@ -157,7 +162,7 @@ func play(screen *ebiten.Image) {
 	canvas.Coord(c2.X, c2.Y, subsize, "control 2", labelcolor)
 
 	// Polygon
-	canvas.CText(colx, 30, labelsize, "Polygon", labelcolor)
+	canvas.CText(colx, row3+above, labelsize, "Polygon", labelcolor)
 	xp := []float32{45, 60, 70, 70, 60, 45}
 	yp := []float32{25, 20, 25, 5, 10, 5}
 	for i := 0; i < len(xp); i++ {
@@ -166,19 +171,19 @@ func play(screen *ebiten.Image) {
 	canvas.StrokedPolygon(xp, yp, lw, stcolor)
 	canvas.Polygon(xp, yp, tcolor)
 
-	colx += 30
+	colx, _ = cols[2].Center()
 	// Rectangles
-	canvas.CText(colx, 80, labelsize, "Rectangle", labelcolor)
-	canvas.CenterRect(colx, 70, 5, 15, fcolor)
-	canvas.Coord(colx, 70, subsize, "center", labelcolor)
+	canvas.CText(colx, row1+above, labelsize, "Rectangle", labelcolor)
+	canvas.CenterRect(colx, row1, 5, 15, fcolor)
+	canvas.Coord(colx, row1, subsize, "center", labelcolor)
 
 	// Square
-	canvas.CText(colx, 55, labelsize, "Square", labelcolor)
-	canvas.Square(colx, 45, 10, tcolor)
-	canvas.Coord(colx, 45, subsize, "center", labelcolor)
+	canvas.CText(colx, row2+above, labelsize, "Square", labelcolor)
+	canvas.Square(colx, row2, 10, tcolor)
+	canvas.Coord(colx, row2, subsize, "center", labelcolor)
 
 	// Image
-	canvas.CText(colx, 30, labelsize, "Image", labelcolor)
+	canvas.CText(colx, row3+above, labelsize, "Image", labelcolor)
 	canvas.Image(colx, 15, 10, earth)
 	canvas.Coord(colx, 15, subsize, "", color.NRGBA{255, 255, 255, 255})
 
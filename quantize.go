@@ -0,0 +1,252 @@
+package ebcanvas
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+// Palette extraction and quantization
+
+// swatch is a group of similar colors: their mean in OKLab, and how many pixels they cover
+type swatch struct {
+	lab    OKLab
+	weight float64
+}
+
+// swatches counts the colors of the images in buckets of 5 bits per component,
+// sampling large images; mostly transparent pixels are counted separately
+func swatches(images []image.Image) ([]swatch, float64) {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := map[uint32]*bucket{}
+	transparent := 0.0
+	total := 0
+	for _, img := range images {
+		b := img.Bounds()
+		total += b.Dx() * b.Dy()
+	}
+	step := 1
+	for total/(step*step) > 1<<20 {
+		step++
+	}
+	for _, img := range images {
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y += step {
+			for x := b.Min.X; x < b.Max.X; x += step {
+				c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				if c.A < 128 {
+					transparent++
+					continue
+				}
+				k := uint32(c.R>>3)<<10 | uint32(c.G>>3)<<5 | uint32(c.B>>3)
+				bk, ok := buckets[k]
+				if !ok {
+					bk = &bucket{}
+					buckets[k] = bk
+				}
+				bk.count++
+				bk.r += int(c.R)
+				bk.g += int(c.G)
+				bk.b += int(c.B)
+			}
+		}
+	}
+	keys := make([]uint32, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] }) // for repeatable results
+	s := make([]swatch, len(keys))
+	for i, k := range keys {
+		bk := buckets[k]
+		n := bk.count
+		c := color.NRGBA{uint8(bk.r / n), uint8(bk.g / n), uint8(bk.b / n), 255}
+		s[i] = swatch{ToOKLab(c), float64(n)}
+	}
+	return s, transparent
+}
+
+// labcomponent returns a component of an OKLab color: 0 is L, 1 is a, 2 is b
+func labcomponent(c OKLab, i int) float64 {
+	switch i {
+	case 0:
+		return c.L
+	case 1:
+		return c.A
+	}
+	return c.B
+}
+
+// box is a set of swatches for median cut
+type box struct {
+	s      []swatch
+	axis   int     // the component with the most variance
+	spread float64 // the weighted variance along the axis
+}
+
+// newbox makes a box, finding the axis to split
+func newbox(s []swatch) box {
+	b := box{s: s}
+	var w float64
+	var sum, sq [3]float64
+	for _, v := range s {
+		w += v.weight
+		for i := 0; i < 3; i++ {
+			c := labcomponent(v.lab, i)
+			sum[i] += v.weight * c
+			sq[i] += v.weight * c * c
+		}
+	}
+	if len(s) < 2 {
+		return b
+	}
+	for i := 0; i < 3; i++ {
+		if e := sq[i] - sum[i]*sum[i]/w; e > b.spread {
+			b.axis, b.spread = i, e
+		}
+	}
+	return b
+}
+
+// split divides a box at the weighted median of its axis
+func (b box) split() (box, box) {
+	s := b.s
+	sort.Slice(s, func(i, j int) bool { return labcomponent(s[i].lab, b.axis) < labcomponent(s[j].lab, b.axis) })
+	var total, w float64
+	for _, v := range s {
+		total += v.weight
+	}
+	n := 1
+	for i, v := range s[:len(s)-1] {
+		w += v.weight
+		n = i + 1
+		if w >= total/2 {
+			break
+		}
+	}
+	return newbox(s[:n]), newbox(s[n:])
+}
+
+// swatchmean returns the weighted mean of swatches
+func swatchmean(s []swatch) swatch {
+	var m swatch
+	for _, v := range s {
+		m.lab.L += v.weight * v.lab.L
+		m.lab.A += v.weight * v.lab.A
+		m.lab.B += v.weight * v.lab.B
+		m.weight += v.weight
+	}
+	if m.weight > 0 {
+		m.lab.L /= m.weight
+		m.lab.A /= m.weight
+		m.lab.B /= m.weight
+	}
+	return m
+}
+
+// labdistance returns the squared distance between OKLab colors
+func labdistance(a, b OKLab) float64 {
+	dl, da, db := a.L-b.L, a.A-b.A, a.B-b.B
+	return dl*dl + da*da + db*db
+}
+
+// ExtractPalette returns at most k colors representing the images, most common first.
+// Colors are grouped by median cut in OKLab, then refined by k-means.
+// If many pixels are transparent, one of the colors is transparent.
+func ExtractPalette(k int, images ...image.Image) []color.NRGBA {
+	s, transparent := swatches(images)
+	var opaque float64
+	for _, v := range s {
+		opaque += v.weight
+	}
+	var pal []color.NRGBA
+	if transparent > 0 && transparent >= (opaque+transparent)/float64(4*max(k, 1)) {
+		pal = append(pal, color.NRGBA{})
+		k--
+	}
+	if k <= 0 || len(s) == 0 {
+		return pal
+	}
+
+	// median cut: split the box with the largest variance until there are k
+	boxes := []box{newbox(s)}
+	for len(boxes) < k {
+		i := 0
+		for j := range boxes {
+			if boxes[j].spread > boxes[i].spread {
+				i = j
+			}
+		}
+		if boxes[i].spread <= 0 {
+			break
+		}
+		a, b := boxes[i].split()
+		boxes[i] = a
+		boxes = append(boxes, b)
+	}
+	centers := make([]swatch, len(boxes))
+	for i, b := range boxes {
+		centers[i] = swatchmean(b.s)
+	}
+
+	// k-means: move each center to the mean of the swatches nearest it
+	groups := make([][]swatch, len(centers))
+	for iter := 0; iter < 4; iter++ {
+		for i := range groups {
+			groups[i] = groups[i][:0]
+		}
+		for _, v := range s {
+			best, bd := 0, math.Inf(1)
+			for i, c := range centers {
+				if d := labdistance(v.lab, c.lab); d < bd {
+					best, bd = i, d
+				}
+			}
+			groups[best] = append(groups[best], v)
+		}
+		for i, g := range groups {
+			if len(g) > 0 {
+				centers[i] = swatchmean(g)
+			}
+		}
+	}
+	sort.SliceStable(centers, func(i, j int) bool { return centers[i].weight > centers[j].weight })
+	for _, c := range centers {
+		pal = append(pal, c.lab.NRGBA())
+	}
+	return pal
+}
+
+// Quantize maps the image to the palette, with Floyd-Steinberg dithering or without.
+// The result has the bounds of the image.
+func Quantize(img image.Image, pal []color.NRGBA, dither bool) *image.Paletted {
+	cp := make(color.Palette, len(pal))
+	for i, c := range pal {
+		cp[i] = c
+	}
+	b := img.Bounds()
+	p := image.NewPaletted(b, cp)
+	if dither {
+		draw.FloydSteinberg.Draw(p, b, img, b.Min)
+	} else {
+		draw.Draw(p, b, img, b.Min, draw.Src)
+	}
+	return p
+}
+
+// ImageTheme returns the theme with accent colors taken from the image, most common first
+func ImageTheme(img image.Image, base Theme, k int) Theme {
+	t := base.clone()
+	t.Accent = nil
+	for _, c := range ExtractPalette(k, img) {
+		if c.A > 0 {
+			t.Accent = append(t.Accent, c)
+		}
+	}
+	return t
+}
@@ -9,6 +9,12 @@ do
 		echart)
 		./allcharts
 		;;
+		ebscene)
+		./ebscene shapes.json &
+		;;
+		ebdraw)
+		./ebdraw flower.dsh &
+		;;
 		elections)
 		./allelections
 		;;
@@ -0,0 +1,117 @@
+package scale
+
+import "math"
+
+// Band divides a range into evenly spaced bands, one for each value of the domain,
+// as used for the bars of a bar chart.
+// Padding is the fraction of each step left between bands (0-1),
+// Outer the padding before the first and after the last band, as a fraction of the step.
+type Band struct {
+	Domain  []string
+	Range   [2]float64
+	Padding float64
+	Outer   float64
+}
+
+// NewBand makes a band scale
+func NewBand(domain []string, r0, r1 float64) *Band {
+	return &Band{Domain: domain, Range: [2]float64{r0, r1}}
+}
+
+// geometry returns the start of the first band, and the signed step and bandwidth
+func (b *Band) geometry() (float64, float64, float64) {
+	n := float64(len(b.Domain))
+	r0, r1 := b.Range[0], b.Range[1]
+	step := (r1 - r0) / math.Max(1, n-b.Padding+2*b.Outer)
+	start := r0 + ((r1-r0)-step*(n-b.Padding))/2
+	return start, step, step * (1 - b.Padding)
+}
+
+// index returns the position of a value in the domain
+func (b *Band) index(v string) int {
+	for i, d := range b.Domain {
+		if d == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Map returns the start of the band for the value
+func (b *Band) Map(v string) (float64, bool) {
+	i := b.index(v)
+	if i < 0 {
+		return 0, false
+	}
+	start, step, _ := b.geometry()
+	return start + float64(i)*step, true
+}
+
+// Center returns the middle of the band for the value
+func (b *Band) Center(v string) (float64, bool) {
+	x, ok := b.Map(v)
+	if !ok {
+		return 0, false
+	}
+	_, _, bw := b.geometry()
+	return x + bw/2, true
+}
+
+// Bandwidth returns the width of each band
+func (b *Band) Bandwidth() float64 {
+	_, _, bw := b.geometry()
+	return math.Abs(bw)
+}
+
+// Step returns the distance between the starts of adjacent bands
+func (b *Band) Step() float64 {
+	_, step, _ := b.geometry()
+	return math.Abs(step)
+}
+
+// Invert returns the value whose band contains the range value
+func (b *Band) Invert(r float64) (string, bool) {
+	start, step, bw := b.geometry()
+	if step == 0 {
+		return "", false
+	}
+	i := math.Floor((r - start) / step)
+	if i < 0 || int(i) >= len(b.Domain) {
+		return "", false
+	}
+	if off := (r - start) - i*step; off/bw > 1 {
+		return "", false // in the padding between bands
+	}
+	return b.Domain[int(i)], true
+}
+
+// Ordinal maps discrete values to discrete outputs, such as colors.
+// Values not in the domain are added to it; outputs are reused when there are more values than outputs.
+type Ordinal struct {
+	Domain []string
+	Range  []string
+}
+
+// NewOrdinal makes an ordinal scale
+func NewOrdinal(domain, r []string) *Ordinal {
+	return &Ordinal{Domain: domain, Range: r}
+}
+
+// Map returns the output for the value
+func (o *Ordinal) Map(v string) string {
+	if len(o.Range) == 0 {
+		return ""
+	}
+	i := -1
+	for j, d := range o.Domain {
+		if d == v {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		o.Domain = append(o.Domain, v)
+		i = len(o.Domain) - 1
+	}
+	return o.Range[i%len(o.Range)]
+}
@@ -0,0 +1,221 @@
+// Package scale maps data values to canvas coordinates.
+//
+// Continuous scales (linear, log, symlog, pow and sqrt) map a numeric domain onto a range,
+// time scales map an interval of time, and band and ordinal scales map discrete values.
+// Ranges are usually percent-based canvas coordinates:
+//
+//	x := scale.NewLinear(0, 365, 10, 90)
+//	y := scale.NewLog(10, 1, 1e6, 10, 90).Nice(5)
+//	canvas.Circle(float32(x.Map(day)), float32(y.Map(count)), 0.5, color)
+//	for _, t := range y.Ticks(5) { ... }
+package scale
+
+import (
+	"math"
+)
+
+// kind identifies the transform of a continuous scale
+type kind int
+
+const (
+	linear kind = iota
+	logarithmic
+	symlog
+	power
+)
+
+// Continuous maps a continuous domain to a range.
+// If Clamp is set, values outside the domain map to the ends of the range.
+type Continuous struct {
+	Domain [2]float64
+	Range  [2]float64
+	Clamp  bool
+
+	kind  kind
+	param float64 // log base, symlog constant or power exponent
+}
+
+// NewLinear makes a linear scale
+func NewLinear(d0, d1, r0, r1 float64) *Continuous {
+	return &Continuous{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}}
+}
+
+// NewLog makes a logarithmic scale with the specified base;
+// the domain must not include or cross zero
+func NewLog(base, d0, d1, r0, r1 float64) *Continuous {
+	return &Continuous{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}, kind: logarithmic, param: base}
+}
+
+// NewSymlog makes a symmetric log scale, linear within constant of zero,
+// and logarithmic beyond; unlike log scales, the domain may include zero
+func NewSymlog(constant, d0, d1, r0, r1 float64) *Continuous {
+	return &Continuous{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}, kind: symlog, param: constant}
+}
+
+// NewPow makes a power scale with the specified exponent
+func NewPow(exponent, d0, d1, r0, r1 float64) *Continuous {
+	return &Continuous{Domain: [2]float64{d0, d1}, Range: [2]float64{r0, r1}, kind: power, param: exponent}
+}
+
+// NewSqrt makes a square root scale, useful for mapping values to the radius of circles
+func NewSqrt(d0, d1, r0, r1 float64) *Continuous {
+	return NewPow(0.5, d0, d1, r0, r1)
+}
+
+// transform maps a domain value to the linear space of the scale
+func (s *Continuous) transform(v float64) float64 {
+	switch s.kind {
+	case logarithmic:
+		if s.Domain[0] < 0 || s.Domain[1] < 0 {
+			return -math.Log(-v) / math.Log(s.param)
+		}
+		return math.Log(v) / math.Log(s.param)
+	case symlog:
+		return math.Copysign(math.Log1p(math.Abs(v)/s.param), v)
+	case power:
+		return math.Copysign(math.Pow(math.Abs(v), s.param), v)
+	}
+	return v
+}
+
+// untransform is the inverse of transform
+func (s *Continuous) untransform(v float64) float64 {
+	switch s.kind {
+	case logarithmic:
+		if s.Domain[0] < 0 || s.Domain[1] < 0 {
+			return -math.Pow(s.param, -v)
+		}
+		return math.Pow(s.param, v)
+	case symlog:
+		return math.Copysign(math.Expm1(math.Abs(v))*s.param, v)
+	case power:
+		return math.Copysign(math.Pow(math.Abs(v), 1/s.param), v)
+	}
+	return v
+}
+
+// Map returns the range value corresponding to the domain value
+func (s *Continuous) Map(v float64) float64 {
+	if s.Clamp {
+		v = clamp(v, s.Domain[0], s.Domain[1])
+	}
+	t0, t1 := s.transform(s.Domain[0]), s.transform(s.Domain[1])
+	r0, r1 := s.Range[0], s.Range[1]
+	if t0 == t1 {
+		return (r0 + r1) / 2
+	}
+	return r0 + (s.transform(v)-t0)/(t1-t0)*(r1-r0)
+}
+
+// Invert returns the domain value corresponding to the range value
+func (s *Continuous) Invert(r float64) float64 {
+	if s.Clamp {
+		r = clamp(r, s.Range[0], s.Range[1])
+	}
+	t0, t1 := s.transform(s.Domain[0]), s.transform(s.Domain[1])
+	r0, r1 := s.Range[0], s.Range[1]
+	if r0 == r1 {
+		return s.untransform((t0 + t1) / 2)
+	}
+	return s.untransform(t0 + (r-r0)/(r1-r0)*(t1-t0))
+}
+
+// Ticks returns about n round values within the domain, in increasing order
+func (s *Continuous) Ticks(n int) []float64 {
+	if s.kind == logarithmic {
+		return s.logticks(n)
+	}
+	return ticks(s.Domain[0], s.Domain[1], n)
+}
+
+// TickFormat returns a format for the values returned by Ticks(n)
+func (s *Continuous) TickFormat(n int) string {
+	if s.kind == logarithmic {
+		return "%g"
+	}
+	return stepformat(tickstep(s.Domain[0], s.Domain[1], n))
+}
+
+// Nice extends the domain to round values, using about n ticks as a guide
+func (s *Continuous) Nice(n int) *Continuous {
+	d0, d1 := s.Domain[0], s.Domain[1]
+	reversed := d1 < d0
+	if reversed {
+		d0, d1 = d1, d0
+	}
+	if s.kind == logarithmic {
+		neg := d1 < 0
+		if neg {
+			d0, d1 = -d1, -d0
+		}
+		lb := math.Log(s.param)
+		d0, d1 = math.Pow(s.param, math.Floor(math.Log(d0)/lb)), math.Pow(s.param, math.Ceil(math.Log(d1)/lb))
+		if neg {
+			d0, d1 = -d1, -d0
+		}
+	} else {
+		d0, d1 = nice(d0, d1, n)
+	}
+	if reversed {
+		d0, d1 = d1, d0
+	}
+	s.Domain = [2]float64{d0, d1}
+	return s
+}
+
+// logticks returns powers of the base within the domain, with intermediate
+// multiples when the domain spans few powers
+func (s *Continuous) logticks(n int) []float64 {
+	d0, d1 := s.Domain[0], s.Domain[1]
+	if d1 < d0 {
+		d0, d1 = d1, d0
+	}
+	neg := d1 < 0
+	if neg {
+		d0, d1 = -d1, -d0
+	}
+	if d0 <= 0 {
+		return nil
+	}
+	lb := math.Log(s.param)
+	i0, i1 := math.Floor(math.Log(d0)/lb), math.Ceil(math.Log(d1)/lb)
+	var t []float64
+	multiples := s.param == math.Trunc(s.param) && i1-i0 < float64(n)
+	for i := i0; i <= i1; i++ {
+		p := math.Pow(s.param, i)
+		if !multiples {
+			if within(p, d0, d1) {
+				t = append(t, p)
+			}
+			continue
+		}
+		for k := 1.0; k < s.param; k++ {
+			if v := k * p; within(v, d0, d1) {
+				t = append(t, v)
+			}
+		}
+	}
+	if neg {
+		for i, j := 0, len(t)-1; i < j; i, j = i+1, j-1 {
+			t[i], t[j] = t[j], t[i]
+		}
+		for i := range t {
+			t[i] = -t[i]
+		}
+	}
+	return t
+}
+
+// within reports whether v is within [lo,hi], allowing for rounding
+func within(v, lo, hi float64) bool {
+	e := (hi - lo) * 1e-12
+	return v >= lo-e && v <= hi+e
+}
+
+// clamp restricts v to lie between a and b, in either order
+func clamp(v, a, b float64) float64 {
+	if b < a {
+		a, b = b, a
+	}
+	return math.Max(a, math.Min(v, b))
+}
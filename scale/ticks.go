@@ -0,0 +1,95 @@
+package scale
+
+import (
+	"fmt"
+	"math"
+)
+
+// limits for choosing steps of 1, 2, 5 or 10 times a power of ten
+var (
+	e10 = math.Sqrt(50)
+	e5  = math.Sqrt(10)
+	e2  = math.Sqrt(2)
+)
+
+// tickstep returns a round step giving about n ticks between a and b
+func tickstep(a, b float64, n int) float64 {
+	if n < 1 {
+		n = 1
+	}
+	step := math.Abs(b-a) / float64(n)
+	if step == 0 || math.IsInf(step, 0) || math.IsNaN(step) {
+		return 0
+	}
+	power := math.Floor(math.Log10(step))
+	e := step / math.Pow(10, power)
+	factor := 1.0
+	switch {
+	case e >= e10:
+		factor = 10
+	case e >= e5:
+		factor = 5
+	case e >= e2:
+		factor = 2
+	}
+	return factor * math.Pow(10, power)
+}
+
+// ticks returns multiples of a round step between a and b, in increasing order
+func ticks(a, b float64, n int) []float64 {
+	if b < a {
+		a, b = b, a
+	}
+	step := tickstep(a, b, n)
+	if step == 0 {
+		if a == b && !math.IsNaN(a) {
+			return []float64{a}
+		}
+		return nil
+	}
+	i0, i1 := math.Ceil(a/step-1e-9), math.Floor(b/step+1e-9)
+	t := make([]float64, 0, int(i1-i0)+1)
+	for i := i0; i <= i1; i++ {
+		t = append(t, round(i*step, step))
+	}
+	return t
+}
+
+// round removes floating point noise from a multiple of step
+func round(v, step float64) float64 {
+	if v == 0 {
+		return 0 // not -0
+	}
+	digits := -math.Floor(math.Log10(step))
+	if digits <= 0 {
+		return v
+	}
+	p := math.Pow(10, digits)
+	return math.Round(v*p) / p
+}
+
+// nice extends a and b to multiples of a round step
+func nice(a, b float64, n int) (float64, float64) {
+	prev := 0.0
+	for i := 0; i < 10; i++ {
+		step := tickstep(a, b, n)
+		if step == 0 || step == prev {
+			break
+		}
+		a, b = math.Floor(a/step)*step, math.Ceil(b/step)*step
+		prev = step
+	}
+	return a, b
+}
+
+// stepformat returns a format showing the decimal places of a step
+func stepformat(step float64) string {
+	if step == 0 {
+		return "%g"
+	}
+	digits := -math.Floor(math.Log10(step) + 1e-9)
+	if digits < 0 {
+		digits = 0
+	}
+	return fmt.Sprintf("%%.%df", int(digits))
+}
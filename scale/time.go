@@ -0,0 +1,189 @@
+package scale
+
+import (
+	"math"
+	"time"
+)
+
+// Time maps an interval of time to a range.
+// If Clamp is set, times outside the domain map to the ends of the range.
+type Time struct {
+	Domain [2]time.Time
+	Range  [2]float64
+	Clamp  bool
+}
+
+// NewTime makes a time scale
+func NewTime(t0, t1 time.Time, r0, r1 float64) *Time {
+	return &Time{Domain: [2]time.Time{t0, t1}, Range: [2]float64{r0, r1}}
+}
+
+// linear returns the equivalent linear scale, with the domain in seconds from the start
+func (s *Time) linear() *Continuous {
+	l := NewLinear(0, s.Domain[1].Sub(s.Domain[0]).Seconds(), s.Range[0], s.Range[1])
+	l.Clamp = s.Clamp
+	return l
+}
+
+// Map returns the range value corresponding to the time
+func (s *Time) Map(t time.Time) float64 {
+	return s.linear().Map(t.Sub(s.Domain[0]).Seconds())
+}
+
+// Invert returns the time corresponding to the range value
+func (s *Time) Invert(r float64) time.Time {
+	secs := s.linear().Invert(r)
+	return s.Domain[0].Add(time.Duration(secs * float64(time.Second)))
+}
+
+// interval is a calendar step for time ticks
+type interval struct {
+	unit   int // second, minute, hour, day, week, month or year
+	n      int
+	approx time.Duration
+	format string
+}
+
+// units of intervals
+const (
+	second = iota
+	minute
+	hour
+	day
+	week
+	month
+	year
+)
+
+const (
+	dayDuration   = 24 * time.Hour
+	monthDuration = 30 * dayDuration
+	yearDuration  = 365 * dayDuration
+)
+
+// intervals, in increasing order
+var intervals = []interval{
+	{second, 1, time.Second, "15:04:05"},
+	{second, 5, 5 * time.Second, "15:04:05"},
+	{second, 15, 15 * time.Second, "15:04:05"},
+	{second, 30, 30 * time.Second, "15:04:05"},
+	{minute, 1, time.Minute, "15:04"},
+	{minute, 5, 5 * time.Minute, "15:04"},
+	{minute, 15, 15 * time.Minute, "15:04"},
+	{minute, 30, 30 * time.Minute, "15:04"},
+	{hour, 1, time.Hour, "15:04"},
+	{hour, 3, 3 * time.Hour, "15:04"},
+	{hour, 6, 6 * time.Hour, "15:04"},
+	{hour, 12, 12 * time.Hour, "Jan 2 15:04"},
+	{day, 1, dayDuration, "Jan 2"},
+	{day, 2, 2 * dayDuration, "Jan 2"},
+	{week, 1, 7 * dayDuration, "Jan 2"},
+	{month, 1, monthDuration, "Jan 2006"},
+	{month, 3, 3 * monthDuration, "Jan 2006"},
+	{month, 6, 6 * monthDuration, "Jan 2006"},
+	{year, 1, yearDuration, "2006"},
+}
+
+// interval returns the calendar interval closest to giving n ticks
+func (s *Time) interval(n int) interval {
+	if n < 1 {
+		n = 1
+	}
+	t0, t1 := s.bounds()
+	target := t1.Sub(t0) / time.Duration(n)
+	best := intervals[0]
+	for _, iv := range intervals {
+		if math.Abs(float64(iv.approx-target)) < math.Abs(float64(best.approx-target)) {
+			best = iv
+		}
+	}
+	if best.unit == year {
+		// multiples of years are chosen like numeric ticks
+		best.n = int(math.Max(1, tickstep(float64(t0.Year()), float64(t1.Year()), n)))
+	}
+	return best
+}
+
+// bounds returns the domain in increasing order
+func (s *Time) bounds() (time.Time, time.Time) {
+	t0, t1 := s.Domain[0], s.Domain[1]
+	if t1.Before(t0) {
+		t0, t1 = t1, t0
+	}
+	return t0, t1
+}
+
+// floor returns the start of the interval containing t
+func (iv interval) floor(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	loc := t.Location()
+	switch iv.unit {
+	case second:
+		return t.Truncate(time.Duration(iv.n) * time.Second)
+	case minute:
+		return time.Date(y, mo, d, t.Hour(), t.Minute()/iv.n*iv.n, 0, 0, loc)
+	case hour:
+		return time.Date(y, mo, d, t.Hour()/iv.n*iv.n, 0, 0, 0, loc)
+	case day:
+		return time.Date(y, mo, d, 0, 0, 0, 0, loc)
+	case week: // Sunday
+		return time.Date(y, mo, d-int(t.Weekday()), 0, 0, 0, 0, loc)
+	case month:
+		return time.Date(y, (mo-1)/time.Month(iv.n)*time.Month(iv.n)+1, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y/iv.n*iv.n, 1, 1, 0, 0, 0, 0, loc)
+	}
+}
+
+// next returns the start of the following interval
+func (iv interval) next(t time.Time) time.Time {
+	switch iv.unit {
+	case second:
+		return t.Add(time.Duration(iv.n) * time.Second)
+	case minute:
+		return t.Add(time.Duration(iv.n) * time.Minute)
+	case hour:
+		return t.Add(time.Duration(iv.n) * time.Hour)
+	case day:
+		return t.AddDate(0, 0, iv.n)
+	case week:
+		return t.AddDate(0, 0, 7*iv.n)
+	case month:
+		return t.AddDate(0, iv.n, 0)
+	default:
+		return t.AddDate(iv.n, 0, 0)
+	}
+}
+
+// Ticks returns about n times at calendar boundaries within the domain, in increasing order
+func (s *Time) Ticks(n int) []time.Time {
+	t0, t1 := s.bounds()
+	iv := s.interval(n)
+	var t []time.Time
+	for tick := iv.floor(t0); !tick.After(t1); tick = iv.next(tick) {
+		if !tick.Before(t0) {
+			t = append(t, tick)
+		}
+	}
+	return t
+}
+
+// TickFormat returns a time layout for the times returned by Ticks(n)
+func (s *Time) TickFormat(n int) string {
+	return s.interval(n).format
+}
+
+// Nice extends the domain to the calendar boundaries used for about n ticks
+func (s *Time) Nice(n int) *Time {
+	t0, t1 := s.bounds()
+	iv := s.interval(n)
+	t0 = iv.floor(t0)
+	if f := iv.floor(t1); f.Before(t1) {
+		t1 = iv.next(f)
+	}
+	if s.Domain[1].Before(s.Domain[0]) {
+		t0, t1 = t1, t0
+	}
+	s.Domain = [2]time.Time{t0, t1}
+	return s
+}
@@ -0,0 +1,325 @@
+package scene
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Node is an element of a scene. Nodes are pointers to the types in this package;
+// their exported fields may be changed at any time, and the scene redraws
+// the affected region on the next Draw.
+type Node interface {
+	// draw renders the node on the canvas
+	draw(c *ec.Canvas, t transform)
+	// bounds returns the region covered by the node
+	bounds(c *ec.Canvas, t transform) layout.Rect
+	// state returns a copy of the node's fields, for detecting changes
+	state() any
+}
+
+// transform maps node coordinates to canvas coordinates
+type transform struct {
+	dx, dy, scale float32
+}
+
+// identity leaves coordinates unchanged
+var identity = transform{scale: 1}
+
+// point transforms a location
+func (t transform) point(x, y float32) (float32, float32) {
+	return x*t.scale + t.dx, y*t.scale + t.dy
+}
+
+// size transforms a measure
+func (t transform) size(v float32) float32 {
+	return v * t.scale
+}
+
+// then combines a group's placement with the enclosing transform
+func (t transform) then(g *Group) transform {
+	s := g.Scale
+	if s == 0 {
+		s = 1
+	}
+	dx, dy := t.point(g.X, g.Y)
+	return transform{dx: dx, dy: dy, scale: t.scale * s}
+}
+
+// aspect returns the ratio of width to height, for converting x measures to y measures
+func aspect(c *ec.Canvas) float32 {
+	if c.Height == 0 {
+		return 1
+	}
+	return float32(c.Width) / float32(c.Height)
+}
+
+// box returns the region from two corners, expanded by pad
+func box(x1, y1, x2, y2, pad float32) layout.Rect {
+	minx, maxx := min(x1, x2), max(x1, x2)
+	miny, maxy := min(y1, y2), max(y1, y2)
+	return layout.Rect{X: minx - pad, Y: miny - pad, W: maxx - minx + 2*pad, H: maxy - miny + 2*pad}
+}
+
+// Group holds nodes placed at (X,Y) and scaled by Scale (0 means 1)
+type Group struct {
+	X, Y     float32
+	Scale    float32
+	Hidden   bool
+	Children []Node
+}
+
+// Add appends nodes to the group, returning the group
+func (g *Group) Add(n ...Node) *Group {
+	g.Children = append(g.Children, n...)
+	return g
+}
+
+// Remove deletes a node from the group
+func (g *Group) Remove(n Node) {
+	for i, c := range g.Children {
+		if c == n {
+			g.Children = append(g.Children[:i], g.Children[i+1:]...)
+			return
+		}
+	}
+}
+
+func (g *Group) draw(c *ec.Canvas, t transform) {
+	if g.Hidden {
+		return
+	}
+	gt := t.then(g)
+	for _, n := range g.Children {
+		n.draw(c, gt)
+	}
+}
+
+func (g *Group) bounds(c *ec.Canvas, t transform) layout.Rect {
+	var r layout.Rect
+	gt := t.then(g)
+	for i, n := range g.Children {
+		if i == 0 {
+			r = n.bounds(c, gt)
+			continue
+		}
+		r = r.Union(n.bounds(c, gt))
+	}
+	return r
+}
+
+func (g *Group) state() any {
+	return [4]any{g.X, g.Y, g.Scale, g.Hidden}
+}
+
+// Rect is a filled rectangle centered at (X,Y), with dimensions (W,H)
+type Rect struct {
+	X, Y, W, H float32
+	Color      color.NRGBA
+}
+
+func (r *Rect) draw(c *ec.Canvas, t transform) {
+	x, y := t.point(r.X, r.Y)
+	c.CenterRect(x, y, t.size(r.W), t.size(r.H), r.Color)
+}
+
+func (r *Rect) bounds(c *ec.Canvas, t transform) layout.Rect {
+	x, y := t.point(r.X, r.Y)
+	w, h := t.size(r.W)/2, t.size(r.H)/2
+	return box(x-w, y-h, x+w, y+h, 0)
+}
+
+func (r *Rect) state() any { return *r }
+
+// Circle is a filled circle centered at (X,Y), with radius R
+type Circle struct {
+	X, Y, R float32
+	Color   color.NRGBA
+}
+
+func (e *Circle) draw(c *ec.Canvas, t transform) {
+	x, y := t.point(e.X, e.Y)
+	c.Circle(x, y, t.size(e.R), e.Color)
+}
+
+func (e *Circle) bounds(c *ec.Canvas, t transform) layout.Rect {
+	x, y := t.point(e.X, e.Y)
+	r := t.size(e.R)
+	ry := r * aspect(c)
+	return box(x-r, y-ry, x+r, y+ry, 0)
+}
+
+func (e *Circle) state() any { return *e }
+
+// Arc is a filled arc centered at (X,Y), with radius R, between angles A1 and A2 (degrees).
+// If Stroke is greater than zero, the arc is stroked instead.
+type Arc struct {
+	X, Y, R, A1, A2 float32
+	Stroke          float32
+	Color           color.NRGBA
+}
+
+func (a *Arc) draw(c *ec.Canvas, t transform) {
+	x, y := t.point(a.X, a.Y)
+	if a.Stroke > 0 {
+		c.StrokedArc(x, y, t.size(a.R), a.A1, a.A2, t.size(a.Stroke), a.Color)
+		return
+	}
+	c.Arc(x, y, t.size(a.R), a.A1, a.A2, a.Color)
+}
+
+func (a *Arc) bounds(c *ec.Canvas, t transform) layout.Rect {
+	x, y := t.point(a.X, a.Y)
+	r := t.size(a.R + a.Stroke)
+	ry := r * aspect(c)
+	return box(x-r, y-ry, x+r, y+ry, 0)
+}
+
+func (a *Arc) state() any { return *a }
+
+// Line is a line from (X1,Y1) to (X2,Y2), with stroke width Size
+type Line struct {
+	X1, Y1, X2, Y2, Size float32
+	Color                color.NRGBA
+}
+
+func (l *Line) draw(c *ec.Canvas, t transform) {
+	x1, y1 := t.point(l.X1, l.Y1)
+	x2, y2 := t.point(l.X2, l.Y2)
+	c.Line(x1, y1, x2, y2, t.size(l.Size), l.Color)
+}
+
+func (l *Line) bounds(c *ec.Canvas, t transform) layout.Rect {
+	x1, y1 := t.point(l.X1, l.Y1)
+	x2, y2 := t.point(l.X2, l.Y2)
+	return box(x1, y1, x2, y2, t.size(l.Size)*aspect(c))
+}
+
+func (l *Line) state() any { return *l }
+
+// Polygon is a filled polygon with the points in X and Y.
+// If Stroke is greater than zero, the polygon is stroked instead.
+type Polygon struct {
+	X, Y   []float32
+	Stroke float32
+	Color  color.NRGBA
+}
+
+func (p *Polygon) draw(c *ec.Canvas, t transform) {
+	n := min(len(p.X), len(p.Y))
+	if n < 2 {
+		return
+	}
+	x := make([]float32, n)
+	y := make([]float32, n)
+	for i := range n {
+		x[i], y[i] = t.point(p.X[i], p.Y[i])
+	}
+	if p.Stroke > 0 {
+		c.StrokedPolygon(x, y, t.size(p.Stroke), p.Color)
+		return
+	}
+	c.Polygon(x, y, p.Color)
+}
+
+func (p *Polygon) bounds(c *ec.Canvas, t transform) layout.Rect {
+	n := min(len(p.X), len(p.Y))
+	if n == 0 {
+		return layout.Rect{}
+	}
+	x1, y1 := t.point(p.X[0], p.Y[0])
+	x2, y2 := x1, y1
+	for i := 1; i < n; i++ {
+		x, y := t.point(p.X[i], p.Y[i])
+		x1, y1 = min(x1, x), min(y1, y)
+		x2, y2 = max(x2, x), max(y2, y)
+	}
+	return box(x1, y1, x2, y2, t.size(p.Stroke)*aspect(c))
+}
+
+func (p *Polygon) state() any {
+	return Polygon{X: append([]float32(nil), p.X...), Y: append([]float32(nil), p.Y...), Stroke: p.Stroke, Color: p.Color}
+}
+
+// Alignment of text
+const (
+	Begin = iota
+	Middle
+	End
+)
+
+// Text is text at (X,Y), aligned at the beginning, middle or end, with optional Rotation (degrees)
+type Text struct {
+	X, Y, Size float32
+	Rotation   float32
+	Align      int
+	S          string
+	Color      color.NRGBA
+}
+
+func (s *Text) draw(c *ec.Canvas, t transform) {
+	x, y := t.point(s.X, s.Y)
+	size := t.size(s.Size)
+	switch {
+	case s.Rotation != 0:
+		c.RText(x, y, s.Rotation, size, s.S, s.Color)
+	case s.Align == Middle:
+		c.CText(x, y, size, s.S, s.Color)
+	case s.Align == End:
+		c.EText(x, y, size, s.S, s.Color)
+	default:
+		c.Text(x, y, size, s.S, s.Color)
+	}
+}
+
+func (s *Text) bounds(c *ec.Canvas, t transform) layout.Rect {
+	x, y := t.point(s.X, s.Y)
+	size := t.size(s.Size)
+	w := c.TextWidth(s.S, size)
+	h := size * aspect(c)
+	if s.Rotation != 0 { // any direction from the start
+		r := max(w, size) * aspect(c)
+		return box(x-r, y-r, x+r, y+r, 0)
+	}
+	switch s.Align {
+	case Middle:
+		x -= w / 2
+	case End:
+		x -= w
+	}
+	return box(x, y-h*0.4, x+w, y+h*1.2, size*0.1)
+}
+
+func (s *Text) state() any { return *s }
+
+// Image is an image centered at (X,Y), at Scale percent of its natural size
+type Image struct {
+	X, Y, Scale float32
+	Img         image.Image
+}
+
+func (i *Image) draw(c *ec.Canvas, t transform) {
+	if i.Img == nil {
+		return
+	}
+	x, y := t.point(i.X, i.Y)
+	c.CenterImage(x, y, t.size(i.Scale), i.Img)
+}
+
+func (i *Image) bounds(c *ec.Canvas, t transform) layout.Rect {
+	if i.Img == nil || c.Width == 0 || c.Height == 0 {
+		return layout.Rect{}
+	}
+	x, y := t.point(i.X, i.Y)
+	s := float64(t.size(i.Scale)/100) * ebiten.Monitor().DeviceScaleFactor()
+	b := i.Img.Bounds()
+	w := float32(math.Ceil(float64(b.Dx())*s)) / float32(c.Width) * 50
+	h := float32(math.Ceil(float64(b.Dy())*s)) / float32(c.Height) * 50
+	return box(x-w, y-h, x+w, y+h, 0)
+}
+
+func (i *Image) state() any { return *i }
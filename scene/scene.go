@@ -0,0 +1,247 @@
+// Package scene is a retained-mode scene graph drawn on an ebiten canvas.
+//
+// A scene is built once, its nodes changed in Update, and drawn with Draw.
+// The scene keeps its rendering between frames, and redraws only
+// the regions covered by nodes that were added, removed or changed.
+package scene
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Scene is a tree of nodes on a background
+type Scene struct {
+	Root       Group
+	Background color.NRGBA
+
+	cache    *ebiten.Image
+	canvas   ec.Canvas
+	prev     map[Node]record
+	redrawn  bool
+	bg       color.NRGBA
+	complete bool // the next draw must render everything
+}
+
+// record is the state of a node when it was last drawn
+type record struct {
+	state  any
+	t      transform
+	bounds layout.Rect
+}
+
+// New makes an empty scene with the specified background
+func New(bg color.NRGBA) *Scene {
+	return &Scene{Background: bg, prev: map[Node]record{}, complete: true}
+}
+
+// Add appends nodes to the root of the scene
+func (s *Scene) Add(n ...Node) *Scene {
+	s.Root.Add(n...)
+	return s
+}
+
+// Invalidate forces the whole scene to be rendered on the next Draw
+func (s *Scene) Invalidate() {
+	s.complete = true
+}
+
+// Redrawn reports whether the last Draw rendered anything
+func (s *Scene) Redrawn() bool {
+	return s.redrawn
+}
+
+// Draw renders changes to the scene, and shows it on the screen
+func (s *Scene) Draw(screen *ebiten.Image) {
+	b := screen.Bounds()
+	if s.cache == nil || s.cache.Bounds().Size() != b.Size() {
+		if s.cache != nil {
+			s.cache.Deallocate()
+		}
+		s.cache = ebiten.NewImage(b.Dx(), b.Dy())
+		s.complete = true
+	}
+	if s.prev == nil {
+		s.prev = map[Node]record{}
+	}
+	s.canvas = ec.Canvas{Width: b.Dx(), Height: b.Dy(), Screen: s.cache}
+	if s.bg != s.Background {
+		s.bg = s.Background
+		s.complete = true
+	}
+
+	dirty, changed := s.changes()
+	s.redrawn = s.complete || changed
+	switch {
+	case s.complete:
+		s.cache.Fill(s.Background)
+		s.Root.draw(&s.canvas, identity)
+		s.complete = false
+	case changed:
+		s.redraw(dirty)
+	}
+	screen.DrawImage(s.cache, nil)
+}
+
+// changes compares the nodes with their last drawn state,
+// returning the union of the old and new regions of the nodes that differ
+func (s *Scene) changes() (layout.Rect, bool) {
+	var dirty layout.Rect
+	changed := false
+	mark := func(r layout.Rect) {
+		if !changed {
+			dirty, changed = r, true
+			return
+		}
+		dirty = dirty.Union(r)
+	}
+	seen := map[Node]bool{}
+	var walk func(g *Group, t transform, hidden bool)
+	walk = func(g *Group, t transform, hidden bool) {
+		hidden = hidden || g.Hidden
+		gt := t.then(g)
+		for _, n := range g.Children {
+			if sub, ok := n.(*Group); ok {
+				walk(sub, gt, hidden)
+				continue
+			}
+			if hidden {
+				continue
+			}
+			seen[n] = true
+			st := n.state()
+			old, ok := s.prev[n]
+			if ok && old.t == gt && reflect.DeepEqual(old.state, st) {
+				continue
+			}
+			r := n.bounds(&s.canvas, gt)
+			if ok {
+				mark(old.bounds)
+			}
+			mark(r)
+			s.prev[n] = record{state: st, t: gt, bounds: r}
+		}
+	}
+	walk(&s.Root, identity, false)
+	for n, old := range s.prev {
+		if !seen[n] {
+			mark(old.bounds)
+			delete(s.prev, n)
+		}
+	}
+	return dirty, changed
+}
+
+// redraw clears the region, and draws the nodes that overlap it, clipped to the region
+func (s *Scene) redraw(r layout.Rect) {
+	cw, ch := float64(s.canvas.Width), float64(s.canvas.Height)
+	x0 := int(math.Floor(float64(r.X)/100*cw)) - 1
+	x1 := int(math.Ceil(float64(r.X+r.W)/100*cw)) + 1
+	y0 := int(math.Floor((100-float64(r.Y+r.H))/100*ch)) - 1
+	y1 := int(math.Ceil((100-float64(r.Y))/100*ch)) + 1
+	clip, ok := s.cache.SubImage(image.Rect(x0, y0, x1, y1)).(*ebiten.Image)
+	if !ok || clip.Bounds().Empty() {
+		return
+	}
+	clip.Fill(s.Background)
+	c := s.canvas
+	c.Screen = clip
+	drawover(&s.Root, &c, identity, r)
+}
+
+// drawover draws the nodes of a group that overlap the region
+func drawover(g *Group, c *ec.Canvas, t transform, r layout.Rect) {
+	if g.Hidden {
+		return
+	}
+	gt := t.then(g)
+	for _, n := range g.Children {
+		if sub, ok := n.(*Group); ok {
+			drawover(sub, c, gt, r)
+			continue
+		}
+		if _, ok := n.bounds(c, gt).Intersect(r); ok {
+			n.draw(c, gt)
+		}
+	}
+}
+
+// Bounds returns the region covered by the node, as last drawn
+func (s *Scene) Bounds(n Node) (layout.Rect, bool) {
+	if g, ok := n.(*Group); ok {
+		t, found := s.find(&s.Root, identity, g)
+		if !found {
+			return layout.Rect{}, false
+		}
+		return g.bounds(&s.canvas, t), true
+	}
+	rec, ok := s.prev[n]
+	return rec.bounds, ok
+}
+
+// find returns the transform enclosing the group
+func (s *Scene) find(g *Group, t transform, target *Group) (transform, bool) {
+	if g == target {
+		return t, true
+	}
+	gt := t.then(g)
+	for _, n := range g.Children {
+		if sub, ok := n.(*Group); ok {
+			if ft, found := s.find(sub, gt, target); found {
+				return ft, true
+			}
+		}
+	}
+	return t, false
+}
+
+// Hit returns the topmost visible node (last drawn) whose region contains (x,y),
+// using percent-based coordinates
+func (s *Scene) Hit(x, y float32) Node {
+	var hit Node
+	var walk func(g *Group)
+	walk = func(g *Group) {
+		if g.Hidden {
+			return
+		}
+		for _, n := range g.Children {
+			if sub, ok := n.(*Group); ok {
+				walk(sub)
+				continue
+			}
+			if rec, ok := s.prev[n]; ok && rec.bounds.Contains(x, y) {
+				hit = n
+			}
+		}
+	}
+	walk(&s.Root)
+	return hit
+}
+
+// HitAll returns all visible nodes whose regions contain (x,y), from the bottom up
+func (s *Scene) HitAll(x, y float32) []Node {
+	var hits []Node
+	var walk func(g *Group)
+	walk = func(g *Group) {
+		if g.Hidden {
+			return
+		}
+		for _, n := range g.Children {
+			if sub, ok := n.(*Group); ok {
+				walk(sub)
+				continue
+			}
+			if rec, ok := s.prev[n]; ok && rec.bounds.Contains(x, y) {
+				hits = append(hits, n)
+			}
+		}
+	}
+	walk(&s.Root)
+	return hits
+}
@@ -0,0 +1,264 @@
+// Package scenefile reads JSON scene descriptions, whose elements map onto
+// Canvas methods, and draws them on a canvas or writes them as SVG.
+//
+// A scene file looks like:
+//
+//	{
+//		"width": 1000, "height": 1000, "background": "white",
+//		"elements": [
+//			{"type": "text", "x": 50, "y": 90, "size": 5, "anchor": "middle", "text": "hello", "color": "black"},
+//			{"type": "circle", "x": 50, "y": 50, "r": 10, "color": "rgb(255,0,0)"},
+//			{"type": "polygon", "points": [[10,10], [20,30], [30,10]], "color": "steelblue", "sw": 0.2}
+//		]
+//	}
+//
+// Coordinates and measures are percentages, as with Canvas methods; colors are ColorLookup strings.
+// Element types are: text, textwrap, circle, rect, cornerrect, square, line, arc, wedge,
+// curve, cubic, polygon, image, grid and coord. Arcs, curves and polygons with a stroke width (sw)
+// are stroked, otherwise they are filled.
+package scenefile
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// Scene is a canvas description
+type Scene struct {
+	Width      int       `json:"width,omitempty"`
+	Height     int       `json:"height,omitempty"`
+	Background string    `json:"background,omitempty"`
+	Elements   []Element `json:"elements"`
+
+	dir    string                 // directory of the scene file, for images
+	images map[string]image.Image // loaded images
+}
+
+// Element is a drawing operation
+type Element struct {
+	Type     string       `json:"type"`
+	X        float32      `json:"x,omitempty"`
+	Y        float32      `json:"y,omitempty"`
+	W        float32      `json:"w,omitempty"`
+	H        float32      `json:"h,omitempty"`
+	R        float32      `json:"r,omitempty"`
+	A1       float32      `json:"a1,omitempty"`
+	A2       float32      `json:"a2,omitempty"`
+	Size     float32      `json:"size,omitempty"`
+	SW       float32      `json:"sw,omitempty"`
+	Rotation float32      `json:"rotation,omitempty"`
+	Scale    float32      `json:"scale,omitempty"`
+	Interval float32      `json:"interval,omitempty"`
+	Points   [][2]float32 `json:"points,omitempty"`
+	Anchor   string       `json:"anchor,omitempty"`
+	Text     string       `json:"text,omitempty"`
+	File     string       `json:"file,omitempty"`
+	Color    string       `json:"color,omitempty"`
+}
+
+const defaultColor = "black"
+
+// Read decodes a scene
+func Read(r io.Reader) (*Scene, error) {
+	s := &Scene{images: map[string]image.Image{}}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(s); err != nil {
+		return nil, err
+	}
+	for i, e := range s.Elements {
+		if err := e.check(); err != nil {
+			return nil, fmt.Errorf("element %d: %v", i+1, err)
+		}
+	}
+	return s, nil
+}
+
+// ReadFile decodes the named scene file; images are found relative to its directory
+func ReadFile(name string) (*Scene, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	s, err := Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	s.dir = filepath.Dir(name)
+	return s, nil
+}
+
+// npoints is the number of points required by element types
+var npoints = map[string]int{
+	"line":    2,
+	"curve":   3,
+	"cubic":   4,
+	"polygon": 3,
+}
+
+// check validates an element
+func (e Element) check() error {
+	switch e.Type {
+	case "text", "textwrap", "circle", "rect", "cornerrect", "square", "arc", "wedge", "coord":
+	case "grid":
+		if e.Interval <= 0 {
+			return fmt.Errorf("grid needs an interval")
+		}
+	case "line", "curve", "cubic", "polygon":
+		n := npoints[e.Type]
+		if e.Type == "polygon" && len(e.Points) < n {
+			return fmt.Errorf("%s needs at least %d points", e.Type, n)
+		}
+		if e.Type != "polygon" && len(e.Points) != n {
+			return fmt.Errorf("%s needs %d points", e.Type, n)
+		}
+	case "image":
+		if e.File == "" {
+			return fmt.Errorf("image needs a file")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", e.Type)
+	}
+	return nil
+}
+
+// color returns the element's color
+func (e Element) color() string {
+	if e.Color == "" {
+		return defaultColor
+	}
+	return e.Color
+}
+
+// xy returns the coordinates of the points
+func (e Element) xy() ([]float32, []float32) {
+	x := make([]float32, len(e.Points))
+	y := make([]float32, len(e.Points))
+	for i, p := range e.Points {
+		x[i], y[i] = p[0], p[1]
+	}
+	return x, y
+}
+
+// image returns a cached image, loading it if needed
+func (s *Scene) image(name string) (image.Image, error) {
+	if s.images == nil {
+		s.images = map[string]image.Image{}
+	}
+	if img, ok := s.images[name]; ok {
+		return img, nil
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.dir, name)
+	}
+	img, err := ec.LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	s.images[name] = img
+	return img, nil
+}
+
+// Draw draws the scene on the canvas
+func (s *Scene) Draw(c *ec.Canvas) error {
+	if s.Background != "" {
+		c.Background(ec.ColorLookup(s.Background))
+	}
+	for _, e := range s.Elements {
+		if err := s.draw(c, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// draw draws an element on the canvas
+func (s *Scene) draw(c *ec.Canvas, e Element) error {
+	color := ec.ColorLookup(e.color())
+	switch e.Type {
+	case "text":
+		switch {
+		case e.Rotation != 0:
+			c.RText(e.X, e.Y, e.Rotation, e.Size, e.Text, color)
+		case e.Anchor == "middle":
+			c.CText(e.X, e.Y, e.Size, e.Text, color)
+		case e.Anchor == "end":
+			c.EText(e.X, e.Y, e.Size, e.Text, color)
+		default:
+			c.Text(e.X, e.Y, e.Size, e.Text, color)
+		}
+	case "textwrap":
+		c.TextWrap(e.X, e.Y, e.W, e.Size, e.Text, color)
+	case "circle":
+		c.Circle(e.X, e.Y, e.R, color)
+	case "rect":
+		c.CenterRect(e.X, e.Y, e.W, e.H, color)
+	case "cornerrect":
+		c.CornerRect(e.X, e.Y, e.W, e.H, color)
+	case "square":
+		c.Square(e.X, e.Y, e.W, color)
+	case "line":
+		p := e.Points
+		c.Line(p[0][0], p[0][1], p[1][0], p[1][1], e.SW, color)
+	case "arc":
+		if e.SW > 0 {
+			c.StrokedArc(e.X, e.Y, e.R, e.A1, e.A2, e.SW, color)
+		} else {
+			c.Arc(e.X, e.Y, e.R, e.A1, e.A2, color)
+		}
+	case "wedge":
+		c.Wedge(e.X, e.Y, e.R, e.A1, e.A2, color)
+	case "curve":
+		p := e.Points
+		if e.SW > 0 {
+			c.StrokedCurve(p[0][0], p[0][1], p[1][0], p[1][1], p[2][0], p[2][1], e.SW, color)
+		} else {
+			c.Curve(p[0][0], p[0][1], p[1][0], p[1][1], p[2][0], p[2][1], color)
+		}
+	case "cubic":
+		p := e.Points
+		if e.SW > 0 {
+			c.StrokedCubeCurve(p[0][0], p[0][1], p[1][0], p[1][1], p[2][0], p[2][1], p[3][0], p[3][1], e.SW, color)
+		} else {
+			c.CubeCurve(p[0][0], p[0][1], p[1][0], p[1][1], p[2][0], p[2][1], p[3][0], p[3][1], color)
+		}
+	case "polygon":
+		x, y := e.xy()
+		if e.SW > 0 {
+			c.StrokedPolygon(x, y, e.SW, color)
+		} else {
+			c.Polygon(x, y, color)
+		}
+	case "image":
+		img, err := s.image(e.File)
+		if err != nil {
+			return err
+		}
+		scale := e.Scale
+		if scale == 0 {
+			scale = 100
+		}
+		if e.Anchor == "corner" {
+			c.CornerImage(e.X, e.Y, float64(scale), img)
+		} else {
+			c.CenterImage(e.X, e.Y, scale, img)
+		}
+	case "grid":
+		sw := e.SW
+		if sw == 0 {
+			sw = 0.1
+		}
+		c.Grid(e.X, e.Y, e.W, e.H, sw, e.Interval, color)
+	case "coord":
+		c.Coord(e.X, e.Y, e.Size, e.Text, color)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package scenefile
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// svgWriter converts percent-based coordinates and measures to SVG user units
+type svgWriter struct {
+	w    *bufio.Writer
+	cw   float64
+	ch   float64
+	scen *Scene
+}
+
+// x converts a percent x coordinate
+func (s *svgWriter) x(v float32) float64 {
+	return float64(v) / 100 * s.cw
+}
+
+// y converts a percent y coordinate (increasing up) to SVG (increasing down)
+func (s *svgWriter) y(v float32) float64 {
+	return (100 - float64(v)) / 100 * s.ch
+}
+
+// m converts a percent measure, relative to the width
+func (s *svgWriter) m(v float32) float64 {
+	return float64(v) / 100 * s.cw
+}
+
+// paint returns the fill or stroke attributes for a color
+func paint(attr, s string) string {
+	c := ec.ColorLookup(s)
+	p := fmt.Sprintf(`%s="rgb(%d,%d,%d)"`, attr, c.R, c.G, c.B)
+	if c.A != 255 {
+		p += fmt.Sprintf(` %s-opacity="%.3g"`, attr, float64(c.A)/255)
+	}
+	return p
+}
+
+// polar returns the SVG location at radius r, angle a (degrees, counter-clockwise) from (cx,cy)
+func polar(cx, cy, r, a float64) (float64, float64) {
+	t := a * math.Pi / 180
+	return cx + r*math.Cos(t), cy - r*math.Sin(t)
+}
+
+// arcpath returns the SVG arc from angle a1 to a2 (degrees, counter-clockwise)
+func arcpath(cx, cy, r, a1, a2 float64) string {
+	x1, y1 := polar(cx, cy, r, a1)
+	if math.Abs(a2-a1) >= 360 {
+		// an arc that ends where it begins draws nothing, so a full circle is two halves
+		xm, ym := polar(cx, cy, r, a1+180)
+		return fmt.Sprintf("M%.2f,%.2f A%.2f,%.2f 0 1,0 %.2f,%.2f A%.2f,%.2f 0 1,0 %.2f,%.2f", x1, y1, r, r, xm, ym, r, r, x1, y1)
+	}
+	x2, y2 := polar(cx, cy, r, a2)
+	large := 0
+	if math.Abs(a2-a1) > 180 {
+		large = 1
+	}
+	return fmt.Sprintf("M%.2f,%.2f A%.2f,%.2f 0 %d,0 %.2f,%.2f", x1, y1, r, r, large, x2, y2)
+}
+
+// WriteSVG writes the scene as SVG, with the specified dimensions
+// (if zero, the dimensions of the scene are used).
+// Text uses the "sans-serif" font family.
+func (sc *Scene) WriteSVG(w io.Writer, width, height int) error {
+	if width == 0 {
+		width = sc.Width
+	}
+	if height == 0 {
+		height = sc.Height
+	}
+	if width == 0 || height == 0 {
+		return fmt.Errorf("scenefile: no dimensions for SVG")
+	}
+	s := &svgWriter{w: bufio.NewWriter(w), cw: float64(width), ch: float64(height), scen: sc}
+	fmt.Fprintf(s.w, "<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	if sc.Background != "" {
+		fmt.Fprintf(s.w, "<rect width=\"%d\" height=\"%d\" %s/>\n", width, height, paint("fill", sc.Background))
+	}
+	for _, e := range sc.Elements {
+		if err := s.element(e); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(s.w, "</svg>")
+	return s.w.Flush()
+}
+
+// element writes an element as SVG
+func (s *svgWriter) element(e Element) error {
+	w := s.w
+	fill := paint("fill", e.color())
+	stroke := paint("stroke", e.color())
+	sw := s.m(e.SW)
+	switch e.Type {
+	case "text":
+		size := s.m(e.Size)
+		anchor := ""
+		switch e.Anchor {
+		case "middle":
+			anchor = ` text-anchor="middle"`
+		case "end":
+			anchor = ` text-anchor="end"`
+		}
+		x, y := s.x(e.X), s.y(e.Y)
+		rotate := ""
+		if e.Rotation != 0 {
+			rotate = fmt.Sprintf(` transform="rotate(%.2f %.2f %.2f)"`, -e.Rotation, x, y)
+			anchor = ""
+		}
+		fmt.Fprintf(w, "<text x=\"%.2f\" y=\"%.2f\" font-family=\"sans-serif\" font-size=\"%.2f\"%s%s %s>%s</text>\n",
+			x, y, size, anchor, rotate, fill, html.EscapeString(e.Text))
+	case "textwrap":
+		s.textwrap(e, fill)
+	case "circle":
+		fmt.Fprintf(w, "<circle cx=\"%.2f\" cy=\"%.2f\" r=\"%.2f\" %s/>\n", s.x(e.X), s.y(e.Y), s.m(e.R), fill)
+	case "rect", "cornerrect":
+		rw, rh := s.m(e.W), float64(e.H)/100*s.ch
+		x, y := s.x(e.X), s.y(e.Y)
+		if e.Type == "rect" {
+			x, y = x-rw/2, y-rh/2
+		}
+		fmt.Fprintf(w, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" %s/>\n", x, y, rw, rh, fill)
+	case "square":
+		side := float64(e.W) / 100 * s.ch
+		fmt.Fprintf(w, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" %s/>\n", s.x(e.X)-side/2, s.y(e.Y)-side/2, side, side, fill)
+	case "line":
+		p := e.Points
+		fmt.Fprintf(w, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke-width=\"%.2f\" %s/>\n",
+			s.x(p[0][0]), s.y(p[0][1]), s.x(p[1][0]), s.y(p[1][1]), sw, stroke)
+	case "arc":
+		d := arcpath(s.x(e.X), s.y(e.Y), s.m(e.R), float64(e.A1), float64(e.A2))
+		if e.SW > 0 {
+			fmt.Fprintf(w, "<path d=\"%s\" fill=\"none\" stroke-width=\"%.2f\" %s/>\n", d, sw, stroke)
+		} else {
+			fmt.Fprintf(w, "<path d=\"%s Z\" %s/>\n", d, fill)
+		}
+	case "wedge":
+		cx, cy := s.x(e.X), s.y(e.Y)
+		d := arcpath(cx, cy, s.m(e.R), float64(e.A1), float64(e.A2))
+		fmt.Fprintf(w, "<path d=\"M%.2f,%.2f L%s Z\" %s/>\n", cx, cy, d[1:], fill)
+	case "curve", "cubic", "polygon":
+		var b strings.Builder
+		for i, p := range e.Points {
+			switch {
+			case i == 0:
+				b.WriteString("M")
+			case i == 1 && e.Type == "curve":
+				b.WriteString(" Q")
+			case i == 1 && e.Type == "cubic":
+				b.WriteString(" C")
+			case e.Type == "polygon":
+				b.WriteString(" L")
+			default:
+				b.WriteString(" ")
+			}
+			fmt.Fprintf(&b, "%.2f,%.2f", s.x(p[0]), s.y(p[1]))
+		}
+		if e.Type == "polygon" {
+			b.WriteString(" Z")
+		}
+		if e.SW > 0 {
+			if e.Type != "polygon" {
+				fmt.Fprintf(w, "<path d=\"%s\" fill=\"none\" stroke-width=\"%.2f\" %s/>\n", b.String(), sw, stroke)
+			} else {
+				fmt.Fprintf(w, "<path d=\"%s\" fill=\"none\" stroke-width=\"%.2f\" stroke-linejoin=\"round\" %s/>\n", b.String(), sw, stroke)
+			}
+		} else {
+			fmt.Fprintf(w, "<path d=\"%s\" %s/>\n", b.String(), fill)
+		}
+	case "image":
+		img, err := s.scen.image(e.File)
+		if err != nil {
+			return err
+		}
+		scale := float64(e.Scale)
+		if scale == 0 {
+			scale = 100
+		}
+		b := img.Bounds()
+		iw, ih := float64(b.Dx())*scale/100, float64(b.Dy())*scale/100
+		x, y := s.x(e.X), s.y(e.Y)
+		if e.Anchor != "corner" {
+			x, y = x-iw/2, y-ih/2
+		}
+		fmt.Fprintf(w, "<image x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" xlink:href=\"%s\"/>\n", x, y, iw, ih, html.EscapeString(e.File))
+	case "grid":
+		gsw := e.SW
+		if gsw == 0 {
+			gsw = 0.1
+		}
+		fmt.Fprintf(w, "<g stroke-width=\"%.2f\" %s>\n", s.m(gsw), stroke)
+		for xp := e.X; xp <= e.X+e.W; xp += e.Interval {
+			fmt.Fprintf(w, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\"/>\n", s.x(xp), s.y(e.Y), s.x(xp), s.y(e.Y+e.H))
+		}
+		for yp := e.Y; yp <= e.Y+e.H; yp += e.Interval {
+			fmt.Fprintf(w, "<line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\"/>\n", s.x(e.X), s.y(yp), s.x(e.X+e.W), s.y(yp))
+		}
+		fmt.Fprintln(w, "</g>")
+	case "coord":
+		size := s.m(e.Size)
+		x, y := s.x(e.X), s.y(e.Y)
+		fmt.Fprintf(w, "<circle cx=\"%.2f\" cy=\"%.2f\" r=\"%.2f\" %s/>\n", x, y, size/4, fill)
+		fmt.Fprintf(w, "<text x=\"%.2f\" y=\"%.2f\" font-family=\"sans-serif\" font-size=\"%.2f\" text-anchor=\"middle\" %s>(%g,%g)</text>\n",
+			x, s.y(e.Y+e.Size), size, fill, e.X, e.Y)
+		if e.Text != "" {
+			fmt.Fprintf(w, "<text x=\"%.2f\" y=\"%.2f\" font-family=\"sans-serif\" font-size=\"%.2f\" text-anchor=\"middle\" %s>%s</text>\n",
+				x, s.y(e.Y-e.Size*1.33), size*0.66, fill, html.EscapeString(e.Text))
+		}
+	}
+	return nil
+}
+
+// textwrap writes wrapped text, estimating word widths from the font size
+func (s *svgWriter) textwrap(e Element, fill string) {
+	const charwidth = 0.55 // approximate advance of a character, relative to the size
+	size := s.m(e.Size)
+	edge := s.x(e.X) + s.m(e.W)
+	x, y := s.x(e.X), s.y(e.Y)
+	space := size * charwidth * 0.6
+	fmt.Fprintf(s.w, "<text font-family=\"sans-serif\" font-size=\"%.2f\" %s>", size, fill)
+	for _, word := range strings.Fields(e.Text) {
+		fmt.Fprintf(s.w, "<tspan x=\"%.2f\" y=\"%.2f\">%s</tspan>", x, y, html.EscapeString(word))
+		x += float64(len([]rune(word)))*size*charwidth + space
+		if x >= edge {
+			x = s.x(e.X)
+			y += size * 1.2
+		}
+	}
+	fmt.Fprintln(s.w, "</text>")
+}
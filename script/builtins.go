@@ -0,0 +1,211 @@
+package script
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"strings"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// builtin is a built-in command
+type builtin func(r *runstate, args []value) error
+
+// builtins maps command names to their implementations
+var builtins = map[string]builtin{
+	"text":  draw("nnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Text(n[0], n[1], n[2], s[0], col) }),
+	"ctext": draw("nnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.CText(n[0], n[1], n[2], s[0], col) }),
+	"etext": draw("nnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.EText(n[0], n[1], n[2], s[0], col) }),
+	"rtext": draw("nnnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.RText(n[0], n[1], n[2], n[3], s[0], col)
+	}),
+	"textwrap": draw("nnnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.TextWrap(n[0], n[1], n[2], n[3], s[0], col)
+	}),
+	"textwrapstrict": draw("nnnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.TextWrapStrict(n[0], n[1], n[2], n[3], s[0], col)
+	}),
+	"circle": draw("nnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Circle(n[0], n[1], n[2], col) }),
+	"rect":   draw("nnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Rect(n[0], n[1], n[2], n[3], col) }),
+	"centerrect": draw("nnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.CenterRect(n[0], n[1], n[2], n[3], col)
+	}),
+	"cornerrect": draw("nnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.CornerRect(n[0], n[1], n[2], n[3], col)
+	}),
+	"square": draw("nnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Square(n[0], n[1], n[2], col) }),
+	"line": draw("nnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.Line(n[0], n[1], n[2], n[3], n[4], col)
+	}),
+	"hline": draw("nnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.HLine(n[0], n[1], n[2], n[3], col) }),
+	"vline": draw("nnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.VLine(n[0], n[1], n[2], n[3], col) }),
+	"arc":   draw("nnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Arc(n[0], n[1], n[2], n[3], n[4], col) }),
+	"strokedarc": draw("nnnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.StrokedArc(n[0], n[1], n[2], n[3], n[4], n[5], col)
+	}),
+	"wedge": draw("nnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.Wedge(n[0], n[1], n[2], n[3], n[4], col)
+	}),
+	"curve": draw("nnnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.Curve(n[0], n[1], n[2], n[3], n[4], n[5], col)
+	}),
+	"strokedcurve": draw("nnnnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.StrokedCurve(n[0], n[1], n[2], n[3], n[4], n[5], n[6], col)
+	}),
+	"cubecurve": draw("nnnnnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.CubeCurve(n[0], n[1], n[2], n[3], n[4], n[5], n[6], n[7], col)
+	}),
+	"strokedcubecurve": draw("nnnnnnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.StrokedCubeCurve(n[0], n[1], n[2], n[3], n[4], n[5], n[6], n[7], n[8], col)
+	}),
+	"grid": draw("nnnnnn", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) {
+		c.Grid(n[0], n[1], n[2], n[3], n[4], n[5], col)
+	}),
+	"coord":          draw("nnns", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Coord(n[0], n[1], n[2], s[0], col) }),
+	"background":     draw("", func(c *ec.Canvas, n []float32, s []string, col color.NRGBA) { c.Background(col) }),
+	"polygon":        polygon,
+	"strokedpolygon": strokedpolygon,
+	"image":          drawimage(false),
+	"cornerimage":    drawimage(true),
+	"seed":           seed,
+	"print":          printargs,
+}
+
+const defaultColor = "black"
+
+// args checks arguments against a spec of numbers (n) and strings (s), followed by an optional color
+func args(spec string, a []value) ([]float32, []string, color.NRGBA, error) {
+	col := ec.ColorLookup(defaultColor)
+	if len(a) != len(spec) && len(a) != len(spec)+1 {
+		return nil, nil, col, fmt.Errorf("needs %d arguments and an optional color", len(spec))
+	}
+	var n []float32
+	var s []string
+	for i, c := range spec {
+		switch {
+		case c == 'n' && a[i].isstr:
+			return nil, nil, col, fmt.Errorf("argument %d: %q is not a number", i+1, a[i].s)
+		case c == 's' && !a[i].isstr:
+			return nil, nil, col, fmt.Errorf("argument %d: %s is not a string", i+1, a[i])
+		case c == 'n':
+			n = append(n, float32(a[i].n))
+		default:
+			s = append(s, a[i].s)
+		}
+	}
+	if len(a) > len(spec) {
+		last := a[len(spec)]
+		if !last.isstr {
+			return nil, nil, col, fmt.Errorf("color %s is not a string", last)
+		}
+		col = ec.ColorLookup(last.s)
+	}
+	return n, s, col, nil
+}
+
+// draw makes a drawing command from a spec and a function using the checked arguments
+func draw(spec string, f func(c *ec.Canvas, n []float32, s []string, col color.NRGBA)) builtin {
+	return func(r *runstate, a []value) error {
+		n, s, col, err := args(spec, a)
+		if err != nil {
+			return err
+		}
+		f(r.canvas, n, s, col)
+		return nil
+	}
+}
+
+// points returns the coordinates and color of "x1 y1 x2 y2 ... [color]", with extra trailing numbers
+func points(a []value, extra int) ([]float32, []float32, []float32, color.NRGBA, error) {
+	col := ec.ColorLookup(defaultColor)
+	if len(a) > 0 && a[len(a)-1].isstr {
+		col = ec.ColorLookup(a[len(a)-1].s)
+		a = a[:len(a)-1]
+	}
+	nc := len(a) - extra
+	if nc < 6 || nc%2 != 0 {
+		return nil, nil, nil, col, fmt.Errorf("needs at least 3 pairs of coordinates")
+	}
+	var x, y, rest []float32
+	for i, v := range a {
+		if v.isstr {
+			return nil, nil, nil, col, fmt.Errorf("argument %d: %q is not a number", i+1, v.s)
+		}
+		switch {
+		case i >= nc:
+			rest = append(rest, float32(v.n))
+		case i%2 == 0:
+			x = append(x, float32(v.n))
+		default:
+			y = append(y, float32(v.n))
+		}
+	}
+	return x, y, rest, col, nil
+}
+
+// polygon: polygon x1 y1 x2 y2 x3 y3 ... [color]
+func polygon(r *runstate, a []value) error {
+	x, y, _, col, err := points(a, 0)
+	if err != nil {
+		return err
+	}
+	r.canvas.Polygon(x, y, col)
+	return nil
+}
+
+// strokedpolygon: strokedpolygon x1 y1 x2 y2 x3 y3 ... size [color]
+func strokedpolygon(r *runstate, a []value) error {
+	x, y, rest, col, err := points(a, 1)
+	if err != nil {
+		return err
+	}
+	r.canvas.StrokedPolygon(x, y, rest[0], col)
+	return nil
+}
+
+// drawimage makes the image commands: image x y scale "file", and cornerimage x y scale "file"
+func drawimage(corner bool) builtin {
+	return func(r *runstate, a []value) error {
+		if len(a) != 4 {
+			return fmt.Errorf("needs 4 arguments")
+		}
+		n, s, _, err := args("nnns", a)
+		if err != nil {
+			return err
+		}
+		img, err := r.prog.image(s[0])
+		if err != nil {
+			return err
+		}
+		if corner {
+			r.canvas.CornerImage(n[0], n[1], float64(n[2]), img)
+		} else {
+			r.canvas.CenterImage(n[0], n[1], n[2], img)
+		}
+		return nil
+	}
+}
+
+// seed: seed n
+func seed(r *runstate, a []value) error {
+	if len(a) != 1 {
+		return fmt.Errorf("needs 1 argument")
+	}
+	n, _, _, err := args("n", a)
+	if err != nil {
+		return err
+	}
+	r.rng = rand.New(rand.NewSource(int64(n[0])))
+	return nil
+}
+
+// printargs: print expr ...
+func printargs(r *runstate, a []value) error {
+	s := make([]string, len(a))
+	for i, v := range a {
+		s[i] = v.String()
+	}
+	_, err := fmt.Fprintln(r.out, strings.Join(s, " "))
+	return err
+}
@@ -0,0 +1,423 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// value is a number or a string
+type value struct {
+	n     float64
+	s     string
+	isstr bool
+}
+
+// num makes a numeric value
+func num(n float64) value { return value{n: n} }
+
+// str makes a string value
+func str(s string) value { return value{s: s, isstr: true} }
+
+// String formats the value
+func (v value) String() string {
+	if v.isstr {
+		return v.s
+	}
+	return strconv.FormatFloat(v.n, 'g', -1, 64)
+}
+
+// truth reports whether a value is true: non-zero, or a non-empty string
+func (v value) truth() bool {
+	if v.isstr {
+		return v.s != ""
+	}
+	return v.n != 0
+}
+
+// expr is an expression
+type expr interface {
+	eval(e *env) (value, error)
+}
+
+type (
+	literal  struct{ v value }
+	variable struct{ name string }
+	unary    struct {
+		op string
+		x  expr
+	}
+	binary struct {
+		op   string
+		x, y expr
+	}
+	funcall struct {
+		name string
+		args []expr
+	}
+)
+
+// token kinds
+const (
+	tnum = iota
+	tstr
+	tident
+	top
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+// operators, longest first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "<", ">", "!", "(", ")", ","}
+
+// lex splits an expression into tokens
+func lex(s string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			j := strings.IndexByte(s[i+1:], '"')
+			if j < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, token{tstr, s[i+1 : i+1+j]})
+			i += j + 2
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1]))):
+			j := i
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.' || s[j] == 'e' ||
+				((s[j] == '-' || s[j] == '+') && j > i && s[j-1] == 'e')) {
+				j++
+			}
+			toks = append(toks, token{tnum, s[i:j]})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{tident, s[i:j]})
+			i = j
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					toks = append(toks, token{top, op})
+					i += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected %q", c)
+			}
+		}
+	}
+	return toks, nil
+}
+
+// parser makes expressions from tokens
+type parser struct {
+	toks []token
+	pos  int
+}
+
+// parseExpr parses a complete expression
+func parseExpr(s string) (expr, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("missing expression")
+	}
+	p := &parser{toks: toks}
+	x, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	return x, nil
+}
+
+// precedence of binary operators
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// peek returns the next token, if any
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.toks) {
+		return token{}, false
+	}
+	return p.toks[p.pos], true
+}
+
+// binary parses operators of at least the specified precedence
+func (p *parser) binary(min int) (expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != top {
+			return x, nil
+		}
+		prec, isbin := precedence[t.text]
+		if !isbin || prec < min {
+			return x, nil
+		}
+		p.pos++
+		y, err := p.binary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: t.text, x: x, y: y}
+	}
+}
+
+// unary parses negation, not, and primary expressions
+func (p *parser) unary() (expr, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("incomplete expression")
+	}
+	if t.kind == top && (t.text == "-" || t.text == "!") {
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: t.text, x: x}, nil
+	}
+	return p.primary()
+}
+
+// primary parses literals, variables, function calls and parenthesized expressions
+func (p *parser) primary() (expr, error) {
+	t, _ := p.peek()
+	p.pos++
+	switch t.kind {
+	case tnum:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", t.text)
+		}
+		return literal{num(n)}, nil
+	case tstr:
+		return literal{str(t.text)}, nil
+	case tident:
+		if next, ok := p.peek(); ok && next.text == "(" {
+			p.pos++
+			var args []expr
+			for {
+				if next, ok := p.peek(); ok && next.text == ")" {
+					p.pos++
+					return funcall{name: t.text, args: args}, nil
+				}
+				if len(args) > 0 {
+					if next, ok := p.peek(); !ok || next.text != "," {
+						return nil, fmt.Errorf("missing , or ) in call of %s", t.text)
+					}
+					p.pos++
+				}
+				a, err := p.binary(0)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, a)
+			}
+		}
+		return variable{t.text}, nil
+	case top:
+		if t.text == "(" {
+			x, err := p.binary(0)
+			if err != nil {
+				return nil, err
+			}
+			if next, ok := p.peek(); !ok || next.text != ")" {
+				return nil, fmt.Errorf("missing )")
+			}
+			p.pos++
+			return x, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (l literal) eval(e *env) (value, error) { return l.v, nil }
+
+func (v variable) eval(e *env) (value, error) {
+	x, ok := e.get(v.name)
+	if !ok {
+		return value{}, fmt.Errorf("undefined variable %s", v.name)
+	}
+	return x, nil
+}
+
+func (u unary) eval(e *env) (value, error) {
+	x, err := u.x.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	if x.isstr {
+		return value{}, fmt.Errorf("%s of a string", u.op)
+	}
+	if u.op == "-" {
+		return num(-x.n), nil
+	}
+	return bool2num(!x.truth()), nil
+}
+
+func (b binary) eval(e *env) (value, error) {
+	x, err := b.x.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	// short circuit logical operators
+	switch b.op {
+	case "&&":
+		if !x.truth() {
+			return num(0), nil
+		}
+	case "||":
+		if x.truth() {
+			return num(1), nil
+		}
+	}
+	y, err := b.y.eval(e)
+	if err != nil {
+		return value{}, err
+	}
+	switch b.op {
+	case "&&", "||":
+		return bool2num(y.truth()), nil
+	case "==":
+		return bool2num(x == y), nil
+	case "!=":
+		return bool2num(x != y), nil
+	}
+	if x.isstr || y.isstr {
+		if b.op == "+" { // concatenation
+			return str(x.String() + y.String()), nil
+		}
+		return value{}, fmt.Errorf("%s of a string", b.op)
+	}
+	switch b.op {
+	case "+":
+		return num(x.n + y.n), nil
+	case "-":
+		return num(x.n - y.n), nil
+	case "*":
+		return num(x.n * y.n), nil
+	case "/":
+		if y.n == 0 {
+			return value{}, fmt.Errorf("division by zero")
+		}
+		return num(x.n / y.n), nil
+	case "%":
+		if y.n == 0 {
+			return value{}, fmt.Errorf("division by zero")
+		}
+		return num(math.Mod(x.n, y.n)), nil
+	case "<":
+		return bool2num(x.n < y.n), nil
+	case "<=":
+		return bool2num(x.n <= y.n), nil
+	case ">":
+		return bool2num(x.n > y.n), nil
+	case ">=":
+		return bool2num(x.n >= y.n), nil
+	}
+	return value{}, fmt.Errorf("unknown operator %s", b.op)
+}
+
+// bool2num converts true and false to 1 and 0
+func bool2num(b bool) value {
+	if b {
+		return num(1)
+	}
+	return num(0)
+}
+
+// math functions available in expressions
+var mathfuncs = map[string]func(...float64) (float64, error){
+	"sin":   unaryfunc(math.Sin),
+	"cos":   unaryfunc(math.Cos),
+	"tan":   unaryfunc(math.Tan),
+	"atan2": binaryfunc(math.Atan2),
+	"sqrt":  unaryfunc(math.Sqrt),
+	"abs":   unaryfunc(math.Abs),
+	"floor": unaryfunc(math.Floor),
+	"ceil":  unaryfunc(math.Ceil),
+	"round": unaryfunc(math.Round),
+	"pow":   binaryfunc(math.Pow),
+	"min":   binaryfunc(math.Min),
+	"max":   binaryfunc(math.Max),
+	"rad":   unaryfunc(func(d float64) float64 { return d * math.Pi / 180 }),
+	"deg":   unaryfunc(func(r float64) float64 { return r * 180 / math.Pi }),
+}
+
+// unaryfunc adapts a function of one argument
+func unaryfunc(f func(float64) float64) func(...float64) (float64, error) {
+	return func(a ...float64) (float64, error) {
+		if len(a) != 1 {
+			return 0, fmt.Errorf("needs 1 argument")
+		}
+		return f(a[0]), nil
+	}
+}
+
+// binaryfunc adapts a function of two arguments
+func binaryfunc(f func(float64, float64) float64) func(...float64) (float64, error) {
+	return func(a ...float64) (float64, error) {
+		if len(a) != 2 {
+			return 0, fmt.Errorf("needs 2 arguments")
+		}
+		return f(a[0], a[1]), nil
+	}
+}
+
+func (f funcall) eval(e *env) (value, error) {
+	args := make([]float64, len(f.args))
+	for i, a := range f.args {
+		v, err := a.eval(e)
+		if err != nil {
+			return value{}, err
+		}
+		if v.isstr {
+			return value{}, fmt.Errorf("%s: string argument", f.name)
+		}
+		args[i] = v.n
+	}
+	if f.name == "random" {
+		if len(args) != 2 {
+			return value{}, fmt.Errorf("random: needs 2 arguments")
+		}
+		return num(args[0] + e.run.rng.Float64()*(args[1]-args[0])), nil
+	}
+	fn, ok := mathfuncs[f.name]
+	if !ok {
+		return value{}, fmt.Errorf("unknown function %s", f.name)
+	}
+	v, err := fn(args...)
+	if err != nil {
+		return value{}, fmt.Errorf("%s: %v", f.name, err)
+	}
+	return num(v), nil
+}
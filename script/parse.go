@@ -0,0 +1,408 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// stripcomment removes a // comment, outside of strings
+func stripcomment(s string) string {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.HasPrefix(s[i:], "//"):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// fields splits a line at spaces outside of strings and parentheses
+func fields(s string) ([]string, error) {
+	var words []string
+	depth, quoted, start := 0, false, -1
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced )")
+			}
+		case unicode.IsSpace(c) && depth == 0:
+			if start >= 0 {
+				words = append(words, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated string")
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("unbalanced (")
+	}
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words, nil
+}
+
+// isname reports whether s is a valid variable or command name
+func isname(s string) bool {
+	for i, c := range s {
+		if !(unicode.IsLetter(c) || c == '_' || (i > 0 && unicode.IsDigit(c))) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// assignment splits "name = expr" or "name=expr"
+func assignment(s string) (string, string, bool) {
+	i := strings.IndexByte(s, '=')
+	if i < 0 || strings.HasPrefix(s[i:], "==") {
+		return "", "", false
+	}
+	name := strings.TrimSpace(s[:i])
+	if !isname(name) {
+		return "", "", false
+	}
+	return name, s[i+1:], true
+}
+
+// stmt is an executable statement
+type stmt interface {
+	exec(e *env) error
+	where() line
+}
+
+type (
+	assign struct {
+		src  line
+		name string
+		x    expr
+	}
+	forloop struct {
+		src              line
+		v                string
+		begin, end, step expr
+		body             []stmt
+	}
+	ifelse struct {
+		src       line
+		cond      expr
+		body, alt []stmt
+	}
+	def struct {
+		src    line
+		name   string
+		params []string
+		body   []stmt
+	}
+	command struct {
+		src  line
+		name string
+		args []expr
+	}
+)
+
+func (s *assign) where() line  { return s.src }
+func (s *forloop) where() line { return s.src }
+func (s *ifelse) where() line  { return s.src }
+func (s *command) where() line { return s.src }
+
+// stmtparser makes statements from lines
+type stmtparser struct {
+	prog  *Program
+	lines []line
+	pos   int
+}
+
+// terminators end blocks
+var terminators = map[string]bool{"efor": true, "eif": true, "else": true, "edef": true}
+
+// block parses statements up to a terminator, returning the terminator ("" at the end of the script)
+func (p *stmtparser) block() ([]stmt, string, error) {
+	var body []stmt
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		p.pos++
+		if name, x, ok := assignment(l.text); ok {
+			e, err := parseExpr(x)
+			if err != nil {
+				return nil, "", l.errorf("%v", err)
+			}
+			body = append(body, &assign{src: l, name: name, x: e})
+			continue
+		}
+		words, _ := fields(l.text) // checked when read
+		if terminators[words[0]] {
+			if len(words) != 1 {
+				return nil, "", l.errorf("unexpected arguments to %s", words[0])
+			}
+			return body, words[0], nil
+		}
+		var s stmt
+		var err error
+		switch words[0] {
+		case "for":
+			s, err = p.forloop(l)
+		case "if":
+			s, err = p.ifelse(l, words)
+		case "def":
+			err = p.def(l, words)
+		default:
+			s, err = p.command(l, words)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if s != nil {
+			body = append(body, s)
+		}
+	}
+	return body, "", nil
+}
+
+// expect parses a block that must end with the specified terminator
+func (p *stmtparser) expect(l line, end string) ([]stmt, string, error) {
+	body, term, err := p.block()
+	if err != nil {
+		return nil, "", err
+	}
+	if term == "" {
+		return nil, "", l.errorf("missing %s", end)
+	}
+	return body, term, nil
+}
+
+// forloop parses "for v = begin end [step]"
+func (p *stmtparser) forloop(l line) (stmt, error) {
+	name, rest, ok := assignment(strings.TrimSpace(l.text)[len("for"):])
+	if !ok {
+		return nil, l.errorf("usage: for v = begin end [step]")
+	}
+	words, _ := fields(rest)
+	if len(words) < 2 || len(words) > 3 {
+		return nil, l.errorf("usage: for v = begin end [step]")
+	}
+	f := &forloop{src: l, v: name, step: literal{num(1)}}
+	targets := []*expr{&f.begin, &f.end, &f.step}
+	for i, w := range words {
+		x, err := parseExpr(w)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		*targets[i] = x
+	}
+	body, term, err := p.expect(l, "efor")
+	if err != nil {
+		return nil, err
+	}
+	if term != "efor" {
+		return nil, l.errorf("for ended by %s", term)
+	}
+	f.body = body
+	return f, nil
+}
+
+// ifelse parses "if cond ... [else ...] eif"
+func (p *stmtparser) ifelse(l line, words []string) (stmt, error) {
+	if len(words) < 2 {
+		return nil, l.errorf("usage: if cond")
+	}
+	cond, err := parseExpr(strings.Join(words[1:], " "))
+	if err != nil {
+		return nil, l.errorf("%v", err)
+	}
+	s := &ifelse{src: l, cond: cond}
+	body, term, err := p.expect(l, "eif")
+	if err != nil {
+		return nil, err
+	}
+	s.body = body
+	if term == "else" {
+		if s.alt, term, err = p.expect(l, "eif"); err != nil {
+			return nil, err
+		}
+	}
+	if term != "eif" {
+		return nil, l.errorf("if ended by %s", term)
+	}
+	return s, nil
+}
+
+// def parses "def name [param ...] ... edef"; definitions are made when parsed
+func (p *stmtparser) def(l line, words []string) error {
+	if len(words) < 2 {
+		return l.errorf("usage: def name [param ...]")
+	}
+	for _, w := range words[1:] {
+		if !isname(w) {
+			return l.errorf("bad name %q", w)
+		}
+	}
+	name := words[1]
+	if _, ok := builtins[name]; ok || keywords[name] {
+		return l.errorf("%s is a built-in command", name)
+	}
+	d := &def{src: l, name: name, params: words[2:]}
+	p.prog.defs[name] = d // defined before the body, allowing recursion
+	body, term, err := p.expect(l, "edef")
+	if err != nil {
+		return err
+	}
+	if term != "edef" {
+		return l.errorf("def ended by %s", term)
+	}
+	d.body = body
+	return nil
+}
+
+// keywords are the statements that are not commands
+var keywords = map[string]bool{"for": true, "if": true, "def": true, "include": true}
+
+// command parses a command and its arguments
+func (p *stmtparser) command(l line, words []string) (stmt, error) {
+	if !isname(words[0]) {
+		return nil, l.errorf("unknown statement %q", words[0])
+	}
+	c := &command{src: l, name: words[0]}
+	for _, w := range words[1:] {
+		x, err := parseExpr(w)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		c.args = append(c.args, x)
+	}
+	return c, nil
+}
+
+// execblock executes statements in order
+func execblock(body []stmt, e *env) error {
+	for _, s := range body {
+		e.run.steps++
+		if e.run.steps > maxsteps {
+			return located{s.where().errorf("too many steps (endless loop?)")}
+		}
+		if err := s.exec(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// locate adds the line to errors that are not already located
+func locate(l line, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(located); ok {
+		return err
+	}
+	return located{l.errorf("%v", err)}
+}
+
+// located is an error with a source location
+type located struct{ error }
+
+func (s *assign) exec(e *env) error {
+	v, err := s.x.eval(e)
+	if err != nil {
+		return locate(s.src, err)
+	}
+	e.set(s.name, v)
+	return nil
+}
+
+func (s *forloop) exec(e *env) error {
+	var bounds [3]float64
+	for i, x := range []expr{s.begin, s.end, s.step} {
+		v, err := x.eval(e)
+		if err != nil {
+			return locate(s.src, err)
+		}
+		if v.isstr {
+			return locate(s.src, fmt.Errorf("for: string bound"))
+		}
+		bounds[i] = v.n
+	}
+	begin, end, step := bounds[0], bounds[1], bounds[2]
+	if step == 0 {
+		return locate(s.src, fmt.Errorf("for: zero step"))
+	}
+	for v := begin; (step > 0 && v <= end) || (step < 0 && v >= end); v += step {
+		// count iterations too, so that a loop with an empty body cannot run without end
+		e.run.steps++
+		if e.run.steps > maxsteps {
+			return located{s.src.errorf("too many steps (endless loop?)")}
+		}
+		e.set(s.v, num(v))
+		if err := execblock(s.body, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ifelse) exec(e *env) error {
+	v, err := s.cond.eval(e)
+	if err != nil {
+		return locate(s.src, err)
+	}
+	if v.truth() {
+		return execblock(s.body, e)
+	}
+	return execblock(s.alt, e)
+}
+
+func (s *command) exec(e *env) error {
+	args := make([]value, len(s.args))
+	for i, x := range s.args {
+		v, err := x.eval(e)
+		if err != nil {
+			return locate(s.src, err)
+		}
+		args[i] = v
+	}
+	if d, ok := e.run.prog.defs[s.name]; ok {
+		if len(args) != len(d.params) {
+			return locate(s.src, fmt.Errorf("%s needs %d arguments", s.name, len(d.params)))
+		}
+		local := &env{vars: map[string]value{}, parent: e.global(), run: e.run}
+		for i, p := range d.params {
+			local.vars[p] = args[i]
+		}
+		if e.run.depth >= maxdepth {
+			return locate(s.src, fmt.Errorf("%s: recursion too deep", s.name))
+		}
+		e.run.depth++
+		defer func() { e.run.depth-- }()
+		return execblock(d.body, local)
+	}
+	b, ok := builtins[s.name]
+	if !ok {
+		return locate(s.src, fmt.Errorf("unknown command %s", s.name))
+	}
+	return locate(s.src, b(e.run, args))
+}
+
+// global returns the outermost scope
+func (e *env) global() *env {
+	for e.parent != nil {
+		e = e.parent
+	}
+	return e
+}
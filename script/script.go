@@ -0,0 +1,248 @@
+// Package script is a small interpreted language for drawing on a canvas.
+//
+// A script is a sequence of lines; each line is an assignment,
+// a control statement, or a command followed by its arguments,
+// separated by spaces:
+//
+//	// a row of circles
+//	n = 5
+//	for i = 1 n 1
+//		x = i * 100 / (n + 1)
+//		circle x 50 5 ("hsv(" + i * 60 + ",100,100)")
+//	efor
+//	ctext 50 20 4 "circles" "black"
+//
+// Arguments are expressions (wrap those containing spaces in parentheses).
+// Values are numbers or strings; + concatenates strings.
+//
+// Control statements are:
+//
+//	for v = begin end [step] ... efor
+//	if cond ... [else ...] eif
+//	def name [param ...] ... edef (define a command)
+//	include "file"
+//	seed n (seed the random function)
+//	print expr ...
+//
+// Drawing commands take the arguments of the Canvas methods of the same name,
+// in the same order; colors are ColorLookup strings, and may be omitted (default black).
+// Expressions may use the functions sin, cos, tan, atan2, sqrt, abs, floor, ceil, round,
+// pow, min, max, rad, deg and random(low, high), and the variables width, height and pi.
+package script
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	ec "github.com/ajstarks/ebcanvas"
+)
+
+// maxsteps limits the statements executed in a run, to catch runaway loops
+const maxsteps = 10000000
+
+// maxdepth limits the nesting of def calls, to catch runaway recursion
+const maxdepth = 1000
+
+// Program is a parsed script
+type Program struct {
+	body   []stmt
+	defs   map[string]*def
+	files  []string
+	dir    string
+	vars   map[string]value
+	images map[string]image.Image
+	output string // the printed output of the last run
+}
+
+// runstate is the state of a run of the program
+type runstate struct {
+	prog   *Program
+	canvas *ec.Canvas
+	rng    *rand.Rand
+	steps  int
+	depth  int
+	out    io.Writer
+}
+
+// env is a scope of variables
+type env struct {
+	vars   map[string]value
+	parent *env
+	run    *runstate
+}
+
+// get returns the value of a variable, looking in enclosing scopes
+func (e *env) get(name string) (value, bool) {
+	for s := e; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+	return value{}, false
+}
+
+// set assigns to a variable in the nearest scope that defines it, or the current scope
+func (e *env) set(name string, v value) {
+	for s := e; s != nil; s = s.parent {
+		if _, ok := s.vars[name]; ok {
+			s.vars[name] = v
+			return
+		}
+	}
+	e.vars[name] = v
+}
+
+// Parse reads a script; name is used in error messages, and includes are relative to dir
+func Parse(r io.Reader, name, dir string) (*Program, error) {
+	p := &Program{defs: map[string]*def{}, dir: dir, vars: map[string]value{}, images: map[string]image.Image{}}
+	lines, err := p.read(r, name)
+	if err != nil {
+		return nil, err
+	}
+	ps := &stmtparser{prog: p, lines: lines}
+	body, end, err := ps.block()
+	if err != nil {
+		return nil, err
+	}
+	if end != "" {
+		l := ps.lines[ps.pos-1]
+		return nil, l.errorf("%s without matching statement", end)
+	}
+	p.body = body
+	return p, nil
+}
+
+// ParseFile reads the named script
+func ParseFile(name string) (*Program, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return Parse(r, name, filepath.Dir(name))
+}
+
+// Files returns the names of the files read by the program, including the script itself
+func (p *Program) Files() []string {
+	return p.files
+}
+
+// Set defines a numeric variable for subsequent runs
+func (p *Program) Set(name string, v float64) {
+	p.vars[name] = num(v)
+}
+
+// Run executes the program, drawing on the canvas.
+// Printed output goes to standard error, only if it differs from that of the previous run,
+// so that a program run every frame does not repeat itself.
+func (p *Program) Run(c *ec.Canvas) error {
+	var out bytes.Buffer
+	run := &runstate{prog: p, canvas: c, rng: rand.New(rand.NewSource(1)), out: &out}
+	global := &env{vars: map[string]value{
+		"width":  num(float64(c.Width)),
+		"height": num(float64(c.Height)),
+		"pi":     num(math.Pi),
+	}, run: run}
+	for k, v := range p.vars {
+		global.vars[k] = v
+	}
+	err := execblock(p.body, global)
+	if s := out.String(); s != p.output {
+		os.Stderr.WriteString(s)
+		p.output = s
+	}
+	return err
+}
+
+// line is a source line
+type line struct {
+	file string
+	num  int
+	text string
+}
+
+// errorf makes an error located at the line
+func (l line) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", l.file, l.num, fmt.Sprintf(format, args...))
+}
+
+// read returns the non-blank lines of a script, expanding includes
+func (p *Program) read(r io.Reader, name string) ([]line, error) {
+	for _, f := range p.files {
+		if f == name {
+			return nil, fmt.Errorf("%s: recursive include", name)
+		}
+	}
+	p.files = append(p.files, name)
+	var lines []line
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		l := line{file: name, num: n, text: stripcomment(scanner.Text())}
+		words, err := fields(l.text)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if words[0] != "include" {
+			lines = append(lines, l)
+			continue
+		}
+		if len(words) != 2 {
+			return nil, l.errorf("usage: include \"file\"")
+		}
+		x, err := parseExpr(words[1])
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		lit, ok := x.(literal)
+		if !ok || !lit.v.isstr {
+			return nil, l.errorf("include needs a quoted file name")
+		}
+		incl, err := p.include(lit.v.s)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		lines = append(lines, incl...)
+	}
+	return lines, scanner.Err()
+}
+
+// include reads the lines of an included file
+func (p *Program) include(name string) ([]line, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, name)
+	}
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return p.read(r, path)
+}
+
+// image returns a cached image, loading it if needed
+func (p *Program) image(name string) (image.Image, error) {
+	if img, ok := p.images[name]; ok {
+		return img, nil
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, name)
+	}
+	img, err := ec.LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+	p.images[name] = img
+	return img, nil
+}
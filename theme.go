@@ -0,0 +1,193 @@
+package ebcanvas
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"maps"
+	"os"
+	"slices"
+)
+
+// Themes
+
+// Theme holds the colors, fonts and text size shared by charts, decks and apps
+type Theme struct {
+	Name       string
+	Background color.NRGBA
+	Foreground color.NRGBA   // text, and titles
+	Accent     []color.NRGBA // colors of data series and categories
+	Grid       color.NRGBA   // grid and guide lines
+	Label      color.NRGBA   // axis and data labels
+	Value      color.NRGBA   // data values
+	Muted      color.NRGBA   // shapes without a color
+	Fonts      map[string]string
+	TextSize   float32 // base text size, percent of the canvas width
+}
+
+// LightTheme is dark text on white
+var LightTheme = Theme{
+	Name:       "light",
+	Background: color.NRGBA{255, 255, 255, 255},
+	Foreground: color.NRGBA{0, 0, 0, 255},
+	Accent:     slices.Clone(palettes["tableau10"]),
+	Grid:       color.NRGBA{128, 128, 128, 128},
+	Label:      color.NRGBA{100, 100, 100, 255},
+	Value:      color.NRGBA{128, 100, 0, 255},
+	Muted:      color.NRGBA{128, 128, 128, 255},
+	Fonts:      maps.Clone(defaultfonts),
+	TextSize:   1.5,
+}
+
+// DarkTheme is light text on near black
+var DarkTheme = Theme{
+	Name:       "dark",
+	Background: color.NRGBA{30, 30, 30, 255},
+	Foreground: color.NRGBA{235, 235, 235, 255},
+	Accent:     slices.Clone(palettes["tableau10"]),
+	Grid:       color.NRGBA{160, 160, 160, 96},
+	Label:      color.NRGBA{170, 170, 170, 255},
+	Value:      color.NRGBA{230, 180, 80, 255},
+	Muted:      color.NRGBA{150, 150, 150, 255},
+	Fonts:      maps.Clone(defaultfonts),
+	TextSize:   1.5,
+}
+
+// defaultfonts are the font names for the roles sans, serif, mono and symbol
+var defaultfonts = map[string]string{
+	"sans":   "PublicSans-Regular",
+	"serif":  "Charter-Regular",
+	"mono":   "Inconsolata-Medium",
+	"symbol": "ZapfDingbats",
+}
+
+// clone returns a copy of the theme that shares no accent colors or fonts with t,
+// so that changing one does not change the other
+func (t Theme) clone() Theme {
+	t.Accent = slices.Clone(t.Accent)
+	t.Fonts = maps.Clone(t.Fonts)
+	return t
+}
+
+// Series returns the accent color for the i-th series or category, reusing colors as needed
+func (t *Theme) Series(i int) color.NRGBA {
+	if len(t.Accent) == 0 {
+		return t.Foreground
+	}
+	return t.Accent[i%len(t.Accent)]
+}
+
+// Font returns the font name for a role (sans, serif, mono or symbol), or "" if there is none
+func (t *Theme) Font(role string) string {
+	return t.Fonts[role]
+}
+
+// themefile is the JSON form of a theme: colors are ColorLookup strings
+type themefile struct {
+	Name       string            `json:"name"`
+	Base       string            `json:"base"`
+	Background string            `json:"background"`
+	Foreground string            `json:"foreground"`
+	Accent     []string          `json:"accent"`
+	Palette    string            `json:"palette"`
+	Grid       string            `json:"grid"`
+	Label      string            `json:"label"`
+	Value      string            `json:"value"`
+	Muted      string            `json:"muted"`
+	Fonts      map[string]string `json:"fonts"`
+	TextSize   float32           `json:"textsize"`
+}
+
+// LoadTheme returns a copy of the built-in theme "light" or "dark", or reads the named theme file
+func LoadTheme(name string) (Theme, error) {
+	switch name {
+	case "", "light":
+		return LightTheme.clone(), nil
+	case "dark":
+		return DarkTheme.clone(), nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return LightTheme.clone(), err
+	}
+	defer f.Close()
+	t, err := ReadTheme(f)
+	if err != nil {
+		return LightTheme.clone(), fmt.Errorf("%s: %v", name, err)
+	}
+	return t, nil
+}
+
+// ReadTheme reads a theme in JSON, for example:
+//
+//	{
+//		"name": "night", "base": "dark",
+//		"background": "midnightblue", "foreground": "#eee",
+//		"palette": "set2", "fonts": {"sans": "Roboto-Regular"}, "textsize": 2
+//	}
+//
+// Anything not given is taken from the base theme, "light" or "dark" (the default is light).
+// Accent colors are a list of colors, or the colors of a named palette.
+func ReadTheme(r io.Reader) (Theme, error) {
+	var tf themefile
+	if err := json.NewDecoder(r).Decode(&tf); err != nil {
+		return LightTheme.clone(), err
+	}
+	var t Theme
+	switch tf.Base {
+	case "", "light":
+		t = LightTheme.clone()
+	case "dark":
+		t = DarkTheme.clone()
+	default:
+		return LightTheme.clone(), fmt.Errorf("unknown base theme %q", tf.Base)
+	}
+	if tf.Name != "" {
+		t.Name = tf.Name
+	}
+	for _, c := range []struct {
+		s string
+		p *color.NRGBA
+	}{
+		{tf.Background, &t.Background},
+		{tf.Foreground, &t.Foreground},
+		{tf.Grid, &t.Grid},
+		{tf.Label, &t.Label},
+		{tf.Value, &t.Value},
+		{tf.Muted, &t.Muted},
+	} {
+		if c.s == "" {
+			continue
+		}
+		v, err := ParseColor(c.s)
+		if err != nil {
+			return LightTheme.clone(), err
+		}
+		*c.p = v
+	}
+	if tf.Palette != "" {
+		colors, ok := PaletteColors(tf.Palette)
+		if !ok {
+			return LightTheme.clone(), fmt.Errorf("unknown palette %q", tf.Palette)
+		}
+		t.Accent = slices.Clone(colors)
+	}
+	if len(tf.Accent) > 0 {
+		t.Accent = make([]color.NRGBA, len(tf.Accent))
+		for i, s := range tf.Accent {
+			v, err := ParseColor(s)
+			if err != nil {
+				return LightTheme.clone(), err
+			}
+			t.Accent[i] = v
+		}
+	}
+	for role, name := range tf.Fonts {
+		t.Fonts[role] = name
+	}
+	if tf.TextSize > 0 {
+		t.TextSize = tf.TextSize
+	}
+	return t, nil
+}
@@ -0,0 +1,86 @@
+package turtle
+
+import (
+	"strings"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
+)
+
+// LSystem is a set of rewrite rules applied to an axiom
+type LSystem struct {
+	Axiom string
+	Rules map[rune]string
+}
+
+// Expand applies the rules n times; symbols without rules are copied.
+// The length of the result grows geometrically with n.
+func (l LSystem) Expand(n int) string {
+	s := l.Axiom
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+		for _, r := range s {
+			if rule, ok := l.Rules[r]; ok {
+				b.WriteString(rule)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		s = b.String()
+	}
+	return s
+}
+
+// Run drives the turtle with the symbols of an expanded L-system:
+//
+//	F, G  move forward by step, drawing
+//	f     move forward by step, without drawing
+//	+ -   turn left or right by angle (degrees)
+//	|     turn around
+//	[ ]   save and restore the position and heading
+//
+// Other symbols are ignored.
+func (t *Turtle) Run(program string, step, angle float32) {
+	for _, r := range program {
+		switch r {
+		case 'F', 'G':
+			t.Forward(step)
+		case 'f':
+			up := t.up
+			t.PenUp()
+			t.Forward(step)
+			t.up = up
+		case '+':
+			t.Left(angle)
+		case '-':
+			t.Right(angle)
+		case '|':
+			t.Left(180)
+		case '[':
+			t.Push()
+		case ']':
+			t.Pop()
+		}
+	}
+}
+
+// Fit returns the starting point and step that fit the drawing of the program,
+// starting with the heading, within the region of the canvas
+func Fit(c *ec.Canvas, r layout.Rect, program string, angle, heading float32) (float32, float32, float32) {
+	t := &Turtle{Heading: heading, canvas: c, dry: true}
+	t.Run(program, 1, angle)
+	b := t.Bounds()
+	step := float32(1)
+	switch {
+	case b.W > 0 && b.H > 0:
+		step = min(r.W/b.W, r.H/b.H)
+	case b.W > 0:
+		step = r.W / b.W
+	case b.H > 0:
+		step = r.H / b.H
+	}
+	// center the scaled drawing in the region
+	x := r.X + (r.W-b.W*step)/2 - b.X*step
+	y := r.Y + (r.H-b.H*step)/2 - b.Y*step
+	return x, y, step
+}
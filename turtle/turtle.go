@@ -0,0 +1,156 @@
+// Package turtle provides turtle graphics on a canvas.
+//
+// A turtle has a position (percent-based coordinates), a heading (degrees,
+// counter-clockwise from east), and a pen. Moving with the pen down draws a line:
+//
+//	t := turtle.New(canvas)
+//	for i := 0; i < 4; i++ {
+//		t.Forward(20)
+//		t.Left(90)
+//	}
+//
+// Vertical movement is adjusted for the aspect ratio of the canvas, so angles look true.
+package turtle
+
+import (
+	"image/color"
+	"math"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/layout"
+)
+
+// Turtle draws on a canvas as it moves
+type Turtle struct {
+	X, Y    float32
+	Heading float32
+	Color   color.NRGBA
+	Width   float32
+
+	canvas  *ec.Canvas
+	up      bool
+	dry     bool // track movement without drawing
+	stack   []state
+	fill    []float32 // vertices of the shape being filled, as x,y pairs
+	filling bool
+	bounds  layout.Rect
+	moved   bool
+}
+
+// state is the saved position of the turtle
+type state struct {
+	x, y, heading float32
+}
+
+// New makes a turtle at the center of the canvas, heading up, with a black pen of width 0.2
+func New(c *ec.Canvas) *Turtle {
+	return &Turtle{X: 50, Y: 50, Heading: 90, Color: color.NRGBA{0, 0, 0, 255}, Width: 0.2, canvas: c}
+}
+
+// aspect returns the ratio of the canvas width to height
+func (t *Turtle) aspect() float32 {
+	if t.canvas == nil || t.canvas.Height == 0 {
+		return 1
+	}
+	return float32(t.canvas.Width) / float32(t.canvas.Height)
+}
+
+// Bounds returns the region covered by the turtle's path, from its first move
+func (t *Turtle) Bounds() layout.Rect {
+	return t.bounds
+}
+
+// Forward moves the turtle d percent in the direction of its heading, drawing if the pen is down
+func (t *Turtle) Forward(d float32) {
+	s, c := math.Sincos(float64(t.Heading) * math.Pi / 180)
+	x := t.X + d*float32(c)
+	y := t.Y + d*float32(s)*t.aspect()
+	t.Goto(x, y)
+}
+
+// Back moves the turtle backwards, without changing its heading
+func (t *Turtle) Back(d float32) {
+	t.Forward(-d)
+}
+
+// Goto moves the turtle to (x,y), drawing if the pen is down
+func (t *Turtle) Goto(x, y float32) {
+	if !t.up && !t.dry && t.canvas != nil {
+		t.canvas.Line(t.X, t.Y, x, y, t.Width, t.Color)
+	}
+	if !t.moved {
+		t.bounds, t.moved = layout.Rect{X: t.X, Y: t.Y}, true
+	}
+	t.bounds = t.bounds.Union(layout.Rect{X: x, Y: y})
+	t.X, t.Y = x, y
+	if t.filling {
+		t.fill = append(t.fill, x, y)
+	}
+}
+
+// Left turns the turtle counter-clockwise by the angle (degrees)
+func (t *Turtle) Left(a float32) {
+	t.Heading = float32(math.Mod(float64(t.Heading+a), 360))
+}
+
+// Right turns the turtle clockwise by the angle (degrees)
+func (t *Turtle) Right(a float32) {
+	t.Left(-a)
+}
+
+// PenUp stops drawing
+func (t *Turtle) PenUp() {
+	t.up = true
+}
+
+// PenDown starts drawing
+func (t *Turtle) PenDown() {
+	t.up = false
+}
+
+// IsDown reports whether the pen is down
+func (t *Turtle) IsDown() bool {
+	return !t.up
+}
+
+// Pen sets the color and width of the pen
+func (t *Turtle) Pen(c color.NRGBA, width float32) {
+	t.Color, t.Width = c, width
+}
+
+// Push saves the position and heading
+func (t *Turtle) Push() {
+	t.stack = append(t.stack, state{t.X, t.Y, t.Heading})
+}
+
+// Pop restores the last saved position and heading, without drawing
+func (t *Turtle) Pop() {
+	n := len(t.stack)
+	if n == 0 {
+		return
+	}
+	s := t.stack[n-1]
+	t.stack = t.stack[:n-1]
+	t.X, t.Y, t.Heading = s.x, s.y, s.heading
+}
+
+// BeginFill starts recording the vertices of a shape to be filled
+func (t *Turtle) BeginFill() {
+	t.filling = true
+	t.fill = append(t.fill[:0], t.X, t.Y)
+}
+
+// EndFill fills the shape traced since BeginFill, with the color
+func (t *Turtle) EndFill(fillcolor color.NRGBA) {
+	t.filling = false
+	n := len(t.fill) / 2
+	if n < 3 || t.dry || t.canvas == nil {
+		return
+	}
+	x := make([]float32, n)
+	y := make([]float32, n)
+	for i := 0; i < n; i++ {
+		x[i], y[i] = t.fill[2*i], t.fill[2*i+1]
+	}
+	t.canvas.Polygon(x, y, fillcolor)
+}
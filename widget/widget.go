@@ -0,0 +1,336 @@
+// Package widget makes immediate-mode user interface controls on an ebiten canvas.
+//
+// Read input in the game's Update with Input.Update, and draw the controls in Draw between
+// BeginInput and End. Presses, releases, typed characters and keys are gathered until the controls
+// use them, and End clears them, so each is used once however often Update and Draw run.
+package widget
+
+import (
+	"fmt"
+	"image/color"
+
+	ec "github.com/ajstarks/ebcanvas"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// stroke is the width of borders and slider tracks
+const stroke = 0.2
+
+// Input is the state of the pointer and keyboard for a frame,
+// using percent-based coordinates
+type Input struct {
+	X, Y      float32 // pointer location
+	Down      bool    // button is held
+	Pressed   bool    // button went down this frame
+	Released  bool    // button went up this frame
+	Chars     []rune  // characters typed this frame
+	Backspace bool
+	Enter     bool
+}
+
+// Update reads the ebiten input state relative to the canvas, keeping the presses,
+// releases, characters and keys not yet used by the controls; call it from the game's Update
+func (in *Input) Update(canvas *ec.Canvas) {
+	n := ReadInput(canvas)
+	n.Pressed = n.Pressed || in.Pressed
+	n.Released = n.Released || in.Released
+	n.Chars = append(in.Chars, n.Chars...)
+	n.Backspace = n.Backspace || in.Backspace
+	n.Enter = n.Enter || in.Enter
+	*in = n
+}
+
+// used clears the presses, releases, characters and keys, once the controls have seen them
+func (in *Input) used() {
+	in.Pressed, in.Released, in.Backspace, in.Enter = false, false, false, false
+	in.Chars = in.Chars[:0]
+}
+
+// UI holds the state of the controls between frames
+type UI struct {
+	Theme  ec.Theme
+	canvas *ec.Canvas
+	in     Input
+	src    *Input // the input to clear at End
+	active string // control being dragged or pressed
+	focus  string // control receiving keyboard input
+	open   string // dropdown showing its options
+}
+
+// New makes a UI with the specified theme: controls are filled with the background color,
+// bordered with the muted color, and highlighted with the first accent color
+func New(t ec.Theme) *UI {
+	return &UI{Theme: t}
+}
+
+// ReadInput returns the current ebiten input state relative to the canvas.
+// Call it from the game's Update, not Draw: ebiten reports presses and releases per tick.
+// Input.Update keeps them until they are used.
+func ReadInput(canvas *ec.Canvas) Input {
+	var in Input
+	px, py := ebiten.CursorPosition()
+	in.X, in.Y = canvas.PercentPoint(px, py)
+	in.Down = ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	in.Pressed = inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	in.Released = inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+	in.Chars = ebiten.AppendInputChars(nil)
+	in.Backspace = repeating(ebiten.KeyBackspace)
+	in.Enter = inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+	return in
+}
+
+// repeating reports whether a held key should repeat
+func repeating(key ebiten.Key) bool {
+	const delay, interval = 30, 3
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d >= delay && (d-delay)%interval == 0)
+}
+
+// BeginInput starts a frame of controls drawn on the canvas using the specified input,
+// whose presses, releases, characters and keys are cleared by End
+func (ui *UI) BeginInput(canvas *ec.Canvas, in *Input) {
+	ui.canvas = canvas
+	ui.in = *in
+	ui.in.Chars = append([]rune(nil), in.Chars...)
+	ui.src = in
+	if in.Pressed {
+		ui.focus = ""
+	}
+	if !in.Down && !in.Released {
+		ui.active = ""
+	}
+}
+
+// End finishes a frame, releasing controls if the button is up,
+// and clears the input's presses, releases, characters and keys
+func (ui *UI) End() {
+	if ui.in.Pressed && ui.active == "" {
+		ui.open = ""
+	}
+	if ui.in.Released {
+		ui.active = ""
+	}
+	if ui.src != nil {
+		ui.src.used()
+	}
+}
+
+// Focused returns the id of the control receiving keyboard input
+func (ui *UI) Focused() string {
+	return ui.focus
+}
+
+// inside reports whether the pointer is in the rectangle centered at (x,y)
+func (ui *UI) inside(x, y, w, h float32) bool {
+	dx, dy := ui.in.X-x, ui.in.Y-y
+	return dx >= -w/2 && dx <= w/2 && dy >= -h/2 && dy <= h/2
+}
+
+// clicked reports whether the control was pressed and released while the pointer is over it
+func (ui *UI) clicked(id string, over bool) bool {
+	if over && ui.in.Pressed {
+		ui.active = id
+	}
+	if ui.active == id && ui.in.Released {
+		ui.active = ""
+		return over
+	}
+	return false
+}
+
+// accent returns the highlight color, the theme's first accent color
+func (ui *UI) accent() color.NRGBA {
+	return ui.Theme.Series(0)
+}
+
+// box draws a bordered box centered at (x,y)
+func (ui *UI) box(x, y, w, h float32, fill color.NRGBA) {
+	aspect := float32(ui.canvas.Width) / float32(ui.canvas.Height)
+	ui.canvas.CenterRect(x, y, w, h, ui.Theme.Muted)
+	ui.canvas.CenterRect(x, y, w-stroke*2, h-stroke*2*aspect, fill)
+}
+
+// label draws centered text in a box centered at (x,y)
+func (ui *UI) label(x, y float32, s string, c color.NRGBA) {
+	ts := ui.Theme.TextSize
+	ui.canvas.CText(x, y-ts/3, ts, s, c)
+}
+
+// Label draws text beginning at (x,y)
+func (ui *UI) Label(x, y float32, s string) {
+	ts := ui.Theme.TextSize
+	ui.canvas.Text(x, y-ts/3, ts, s, ui.Theme.Label)
+}
+
+// Button draws a button centered at (x,y), with dimensions (w,h),
+// returning true when clicked
+func (ui *UI) Button(id string, x, y, w, h float32, s string) bool {
+	over := ui.inside(x, y, w, h)
+	fill := ui.Theme.Background
+	if ui.active == id && over {
+		fill = ui.accent()
+	}
+	ui.box(x, y, w, h, fill)
+	ui.label(x, y, s, ui.Theme.Foreground)
+	return ui.clicked(id, over)
+}
+
+// Toggle draws a checkbox centered at (x,y) with a label to the right,
+// flipping *v and returning true when clicked
+func (ui *UI) Toggle(id string, x, y, size float32, s string, v *bool) bool {
+	aspect := float32(ui.canvas.Width) / float32(ui.canvas.Height)
+	h := size * aspect
+	over := ui.inside(x, y, size, h)
+	ui.box(x, y, size, h, ui.Theme.Background)
+	if *v {
+		ui.canvas.CenterRect(x, y, size*0.5, h*0.5, ui.accent())
+	}
+	ui.Label(x+size, y, s)
+	if ui.clicked(id, over) {
+		*v = !*v
+		return true
+	}
+	return false
+}
+
+// Slider draws a horizontal slider beginning at (x,y), with length w,
+// setting *v between min and max while dragged, and returning true when *v changes
+func (ui *UI) Slider(id string, x, y, w float32, v *float32, min, max float32) bool {
+	t := ui.Theme
+	knob := t.TextSize * 0.6
+	aspect := float32(ui.canvas.Width) / float32(ui.canvas.Height)
+	over := ui.inside(x+w/2, y, w+knob*2, knob*2*aspect)
+	if over && ui.in.Pressed {
+		ui.active = id
+	}
+	changed := false
+	if ui.active == id && ui.in.Down && max > min {
+		nv := float32(ec.MapRange(float64(ui.in.X), float64(x), float64(x+w), float64(min), float64(max)))
+		nv = clamp(nv, min, max)
+		if nv != *v {
+			*v = nv
+			changed = true
+		}
+	}
+	kx := x
+	if max > min {
+		kx = float32(ec.MapRange(float64(clamp(*v, min, max)), float64(min), float64(max), float64(x), float64(x+w)))
+	}
+	ui.canvas.Line(x, y, x+w, y, stroke*2, t.Muted)
+	ui.canvas.Line(x, y, kx, y, stroke*2, ui.accent())
+	ui.canvas.Circle(kx, y, knob, ui.accent())
+	return changed
+}
+
+// Stepper draws a numeric stepper centered at (x,y) with dimensions (w,h),
+// adding or subtracting step from *v within min and max, and returning true when *v changes
+func (ui *UI) Stepper(id string, x, y, w, h float32, v *float32, step, min, max float32, format string) bool {
+	bw := w / 4
+	changed := false
+	if ui.Button(id+"-", x-w/2+bw/2, y, bw, h, "-") {
+		*v = clamp(*v-step, min, max)
+		changed = true
+	}
+	ui.box(x, y, w-bw*2, h, ui.Theme.Background)
+	ui.label(x, y, fmt.Sprintf(format, *v), ui.Theme.Foreground)
+	if ui.Button(id+"+", x+w/2-bw/2, y, bw, h, "+") {
+		*v = clamp(*v+step, min, max)
+		changed = true
+	}
+	return changed
+}
+
+// Dropdown draws a list of choices centered at (x,y), with dimensions (w,h),
+// showing the current choice *v; when open the choices are listed below.
+// Dropdown returns true when a new choice is selected.
+// Draw dropdowns after other controls, so that their lists are on top.
+func (ui *UI) Dropdown(id string, x, y, w, h float32, choices []string, v *int) bool {
+	cur := ""
+	if *v >= 0 && *v < len(choices) {
+		cur = choices[*v]
+	}
+	over := ui.inside(x, y, w, h)
+	ui.box(x, y, w, h, ui.Theme.Background)
+	ui.label(x, y, cur, ui.Theme.Foreground)
+	ui.canvas.CText(x+w/2-h/2, y-ui.Theme.TextSize/3, ui.Theme.TextSize, "v", ui.accent())
+	if ui.open != id {
+		if ui.clicked(id, over) {
+			ui.open = id
+		}
+		return false
+	}
+	if over && ui.in.Pressed {
+		ui.active = id
+		ui.open = ""
+		return false
+	}
+	for i, s := range choices {
+		iy := y - float32(i+1)*h
+		iover := ui.inside(x, iy, w, h)
+		fill := ui.Theme.Background
+		if iover || i == *v {
+			fill = ui.accent()
+		}
+		ui.box(x, iy, w, h, fill)
+		ui.label(x, iy, s, ui.Theme.Foreground)
+		if iover && ui.in.Pressed {
+			ui.active = id
+			ui.open = ""
+			changed := *v != i
+			*v = i
+			return changed
+		}
+	}
+	if ui.in.Pressed {
+		ui.active = id
+		ui.open = ""
+	}
+	return false
+}
+
+// TextField draws a single line text field centered at (x,y), with dimensions (w,h),
+// editing *s while it has focus, returning true when editing is finished with Enter
+func (ui *UI) TextField(id string, x, y, w, h float32, s *string) bool {
+	t := ui.Theme
+	over := ui.inside(x, y, w, h)
+	if over && ui.in.Pressed {
+		ui.focus = id
+	}
+	done := false
+	border := t.Background
+	if ui.focus == id {
+		border = ui.accent()
+		r := []rune(*s)
+		r = append(r, ui.in.Chars...)
+		if ui.in.Backspace && len(r) > 0 {
+			r = r[:len(r)-1]
+		}
+		*s = string(r)
+		if ui.in.Enter {
+			ui.focus = ""
+			done = true
+		}
+	}
+	ui.box(x, y, w, h, border)
+	ui.canvas.CenterRect(x, y, w*0.98, h*0.8, t.Background)
+	tx := x - w/2 + t.TextSize/2
+	ty := y - t.TextSize/3
+	ui.canvas.Text(tx, ty, t.TextSize, *s, t.Foreground)
+	if ui.focus == id {
+		cx := tx + ui.canvas.TextWidth(*s, t.TextSize)
+		ui.canvas.Line(cx, y-h*0.3, cx, y+h*0.3, stroke, t.Foreground)
+	}
+	return done
+}
+
+// clamp limits v to the range min-max
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
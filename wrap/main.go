@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/ajstarks/ebcanvas"
+	"github.com/ajstarks/ebcanvas/widget"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
@@ -14,6 +15,8 @@ type App struct {
 	tx float32
 	tw float32
 	ts float32
+	ui *widget.UI
+	in widget.Input
 }
 
 func (a *App) Update() error {
@@ -38,8 +41,7 @@ func (a *App) Update() error {
 	if inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) {
 		a.tx -= 1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
 		a.tw += 1
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) ||
@@ -57,6 +59,7 @@ func (a *App) Update() error {
 	if a.ts > 10 {
 		a.ts = 2
 	}
+	a.in.Update(&ebcanvas.Canvas{Width: screenWidth, Height: screenHeight})
 	return nil
 }
 
@@ -89,7 +92,20 @@ func work(a *App, screen *ebiten.Image) {
 	canvas.Background(bgcolor)
 	wrap(canvas, false, a.tx, 95, a.tw, a.ts, tm, txcolor)
 	wrap(canvas, true, a.tx, 45, a.tw, a.ts, tm, txcolor)
-	canvas.EText(95, 2, 2, fmt.Sprintf("tx=%v tw=%v ts=%v", a.tx, a.tw, a.ts), txcolor)
+	controls(a, canvas)
+}
+
+// controls adjusts the margin, width and text size with sliders
+func controls(a *App, canvas *ebcanvas.Canvas) {
+	ui := a.ui
+	ui.BeginInput(canvas, &a.in)
+	ui.Label(5, 4, fmt.Sprintf("x=%.1f", a.tx))
+	ui.Slider("tx", 15, 4, 15, &a.tx, 0, 100)
+	ui.Label(35, 4, fmt.Sprintf("width=%.1f", a.tw))
+	ui.Slider("tw", 47, 4, 15, &a.tw, 5, 100)
+	ui.Label(67, 4, fmt.Sprintf("size=%.1f", a.ts))
+	ui.Slider("ts", 78, 4, 15, &a.ts, 0.5, 10)
+	ui.End()
 }
 
 func wrap(canvas *ebcanvas.Canvas, strict bool, x, y, w, size float32, s string, color color.NRGBA) {
@@ -111,7 +127,7 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return
 	}
-	if err := ebiten.RunGame(&App{}); err != nil {
+	if err := ebiten.RunGame(&App{ui: widget.New(ebcanvas.DarkTheme)}); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return
 	}